@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+)
+
+// FaxterAPI is the part of the API client that resources call. It is
+// satisfied by *faxter.Client and, for unit tests of resource logic without
+// an API, by the in-memory *faxtertest.FakeAPI:
+//
+//	c := NewClient("", "")
+//	c.api = faxtertest.NewFakeAPI()
+//
+// Configuration, data sources and listing go through the REST client
+// directly, see Client.rest.
+type FaxterAPI interface {
+	Do(ctx context.Context, method, path string, in, out interface{}) error
+
+	CreateProject(ctx context.Context, name string) error
+	ProjectExists(ctx context.Context, name string) (bool, error)
+	RenameProject(ctx context.Context, name, newName string) error
+	DeleteProject(ctx context.Context, name string) error
+	GetProjectPublicKey(ctx context.Context, project string) (*faxter.ProjectPublicKeyResponse, error)
+
+	CreateServer(ctx context.Context, req *faxter.ServerCreateRequest) ([]faxter.ResourceResponse, error)
+	GetServer(ctx context.Context, project, name string) (*faxter.ResourceResponse, error)
+	ListServers(ctx context.Context, project string) ([]faxter.ResourceResponse, error)
+	UpdateServer(ctx context.Context, project, name string, req *faxter.ServerUpdateRequest) error
+	DeleteServer(ctx context.Context, project, name string) error
+	RebuildServer(ctx context.Context, project, name string, req *faxter.ServerRebuildRequest) error
+	AttachVolume(ctx context.Context, project, server, volume string) error
+	DetachVolume(ctx context.Context, project, server, volume string) error
+	ListServerEvents(ctx context.Context, project, name string) ([]faxter.ServerEvent, error)
+	GetServerPassword(ctx context.Context, project, name string) (*faxter.ServerPasswordResponse, error)
+	StartExec(ctx context.Context, project, server string, req *faxter.ExecRequest) (*faxter.ExecResponse, error)
+	GetExec(ctx context.Context, project, server, id string) (*faxter.ExecResponse, error)
+
+	CreateSSHKey(ctx context.Context, req *faxter.SSHKeyCreateRequest) (*faxter.ResourceResponse, error)
+	GetSSHKey(ctx context.Context, name string) (*faxter.ResourceResponse, error)
+	UpdateSSHKey(ctx context.Context, name string, req *faxter.SSHKeyUpdateRequest) error
+	DeleteSSHKey(ctx context.Context, name string) error
+
+	CreateNetwork(ctx context.Context, req *faxter.NetworkCreateRequest) (*faxter.ResourceResponse, error)
+	GetNetwork(ctx context.Context, project, name string) (*faxter.NetworkResponse, error)
+	UpdateNetwork(ctx context.Context, project, name string, req *faxter.NetworkCreateRequest) error
+	DeleteNetwork(ctx context.Context, project, name string) error
+
+	CreateRouter(ctx context.Context, req *faxter.RouterCreateRequest) (*faxter.ResourceResponse, error)
+	GetRouter(ctx context.Context, project, name string) (*faxter.ResourceResponse, error)
+	UpdateRouter(ctx context.Context, project, name string, req *faxter.RouterCreateRequest) error
+	DeleteRouter(ctx context.Context, project, name string) error
+
+	CreateGatewayService(ctx context.Context, req *faxter.GatewayServiceRequest) error
+	GetGatewayService(ctx context.Context, project, name string) (*faxter.GatewayServiceResponse, error)
+	UpdateGatewayService(ctx context.Context, project, name string, req *faxter.GatewayServiceUpdateRequest) error
+	DeleteGatewayService(ctx context.Context, project, name string) error
+
+	CreateQoSPolicy(ctx context.Context, req *faxter.QoSPolicyRequest) error
+	GetQoSPolicy(ctx context.Context, project, name string) (*faxter.QoSPolicyResponse, error)
+	UpdateQoSPolicy(ctx context.Context, project, name string, req *faxter.QoSPolicyRequest) error
+	DeleteQoSPolicy(ctx context.Context, project, name string) error
+
+	CreateSecurityGroup(ctx context.Context, req *faxter.SecurityGroupCreateRequest) (*faxter.ResourceResponse, error)
+	GetSecurityGroup(ctx context.Context, project, name string) (*faxter.ResourceResponse, error)
+	UpdateSecurityGroup(ctx context.Context, project, name string, req *faxter.SecurityGroupCreateRequest) error
+	DeleteSecurityGroup(ctx context.Context, project, name string) error
+
+	CreateSecurityGroupRule(ctx context.Context, project, securityGroup string, req *faxter.SecurityGroupRuleRequest) (*faxter.SecurityGroupRuleResponse, error)
+	GetSecurityGroupRule(ctx context.Context, project, securityGroup, ruleID string) (*faxter.SecurityGroupRuleResponse, error)
+	ListSecurityGroupRules(ctx context.Context, project, securityGroup string) ([]faxter.SecurityGroupRuleResponse, error)
+	DeleteSecurityGroupRule(ctx context.Context, project, securityGroup, ruleID string) error
+
+	CreateVolume(ctx context.Context, req *faxter.VolumeCreateRequest) (*faxter.ResourceResponse, error)
+	GetVolume(ctx context.Context, project, name string) (*faxter.ResourceResponse, error)
+	UpdateVolume(ctx context.Context, project, name string, req *faxter.VolumeUpdateRequest) error
+	DeleteVolume(ctx context.Context, project, name string) error
+
+	CreateLoadBalancer(ctx context.Context, req *faxter.LoadBalancerCreateRequest) (*faxter.LoadBalancerResponse, error)
+	GetLoadBalancer(ctx context.Context, project, name string) (*faxter.LoadBalancerResponse, error)
+	UpdateLoadBalancer(ctx context.Context, project, name string, req *faxter.LoadBalancerUpdateRequest) error
+	DeleteLoadBalancer(ctx context.Context, project, name string) error
+	AddLoadBalancerMember(ctx context.Context, project, name string, item faxter.ServerItem) error
+	RemoveLoadBalancerMember(ctx context.Context, project, name string, item faxter.ServerItem) error
+
+	CreateLBProfile(ctx context.Context, req *faxter.LBProfileRequest) error
+	GetLBProfile(ctx context.Context, project, name string) (*faxter.LBProfileResponse, error)
+	UpdateLBProfile(ctx context.Context, project, name string, req *faxter.LBProfileRequest) error
+	DeleteLBProfile(ctx context.Context, project, name string) error
+
+	CreatePlacementPolicy(ctx context.Context, req *faxter.PlacementPolicyRequest) error
+	GetPlacementPolicy(ctx context.Context, project, name string) (*faxter.PlacementPolicyResponse, error)
+	UpdatePlacementPolicy(ctx context.Context, project, name string, req *faxter.PlacementPolicyRequest) error
+	DeletePlacementPolicy(ctx context.Context, project, name string) error
+
+	CreateReverseDNS(ctx context.Context, req *faxter.ReverseDNSRequest) error
+	GetReverseDNS(ctx context.Context, project, floatingIP string) (*faxter.ReverseDNSResponse, error)
+	UpdateReverseDNS(ctx context.Context, project, floatingIP string, req *faxter.ReverseDNSRequest) error
+	DeleteReverseDNS(ctx context.Context, project, floatingIP string) error
+
+	CreateHostAggregate(ctx context.Context, req *faxter.HostAggregateRequest) error
+	GetHostAggregate(ctx context.Context, name string) (*faxter.HostAggregateResponse, error)
+	UpdateHostAggregate(ctx context.Context, name string, req *faxter.HostAggregateRequest) error
+	DeleteHostAggregate(ctx context.Context, name string) error
+
+	CreateImageMember(ctx context.Context, project, image, member string) (*faxter.ImageMemberResponse, error)
+	GetImageMember(ctx context.Context, project, image, member string) (*faxter.ImageMemberResponse, error)
+	SetImageMemberStatus(ctx context.Context, image, member, status string) error
+	DeleteImageMember(ctx context.Context, project, image, member string) error
+
+	CreateBillingAlert(ctx context.Context, req *faxter.BillingAlertRequest) error
+	GetBillingAlert(ctx context.Context, project, name string) (*faxter.BillingAlertResponse, error)
+	UpdateBillingAlert(ctx context.Context, project, name string, req *faxter.BillingAlertRequest) error
+	DeleteBillingAlert(ctx context.Context, project, name string) error
+
+	CreateQuotaRequest(ctx context.Context, req *faxter.QuotaRequestRequest) (*faxter.QuotaRequestResponse, error)
+	GetQuotaRequest(ctx context.Context, project, id string) (*faxter.QuotaRequestResponse, error)
+	WithdrawQuotaRequest(ctx context.Context, project, id string) error
+
+	PutBucketPolicy(ctx context.Context, project, bucket string, req *faxter.BucketPolicyRequest) error
+	GetBucketPolicy(ctx context.Context, project, bucket string) (*faxter.BucketPolicyResponse, error)
+	DeleteBucketPolicy(ctx context.Context, project, bucket string) error
+
+	Lock(ctx context.Context, collection, project, name, reason string) error
+	GetLock(ctx context.Context, collection, project, name string) (*faxter.LockResponse, error)
+	Unlock(ctx context.Context, collection, project, name string) error
+}
+
+var _ FaxterAPI = (*faxter.Client)(nil)
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// breakerTransport stops sending requests once the API has failed threshold
+// of them in a row, so that an apply against an API that is down fails
+// within seconds with one clear error rather than after every resource has
+// exhausted its retries. A failure is a request that got no response or a
+// 5xx one; it sits above the retries, so a request counts once however often
+// it was retried. After cooldown one request is let through to probe the
+// API: if it succeeds requests flow again, otherwise the breaker stays open
+// for another cooldown.
+type breakerTransport struct {
+	threshold int
+	cooldown  time.Duration
+	base      http.RoundTripper
+
+	mu       sync.Mutex
+	failures int
+	lastErr  string
+	openedAt time.Time
+	probing  bool
+}
+
+// circuitOpenError is returned for requests the breaker didn't send.
+type circuitOpenError struct {
+	failures int
+	lastErr  string
+	retryAt  time.Time
+}
+
+func (e *circuitOpenError) Error() string {
+	return fmt.Sprintf("request not sent: the Faxter API failed %d requests in a row, most recently with %s; it will be tried again in %s",
+		e.failures, e.lastErr, time.Until(e.retryAt).Round(time.Second))
+}
+
+func newBreakerTransport(threshold int, cooldown time.Duration, base http.RoundTripper) *breakerTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &breakerTransport{
+		threshold: threshold,
+		cooldown:  cooldown,
+		base:      base,
+	}
+}
+
+func (t *breakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	probe, err := t.allow()
+	if err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	switch {
+	case err != nil && errors.Is(err, context.Canceled):
+		// The caller gave up; that says nothing about the API.
+		t.release(probe)
+	case err != nil:
+		t.failed(req, probe, err.Error())
+	case resp.StatusCode >= 500:
+		t.failed(req, probe, resp.Status)
+	default:
+		t.succeeded()
+	}
+	return resp, err
+}
+
+// allow reports whether a request may be sent, and whether it is the probe
+// of an open breaker.
+func (t *breakerTransport) allow() (bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.failures < t.threshold {
+		return false, nil
+	}
+	retryAt := t.openedAt.Add(t.cooldown)
+	if !t.probing && !time.Now().Before(retryAt) {
+		t.probing = true
+		return true, nil
+	}
+	return false, &circuitOpenError{failures: t.failures, lastErr: t.lastErr, retryAt: retryAt}
+}
+
+func (t *breakerTransport) release(probe bool) {
+	if probe {
+		t.mu.Lock()
+		t.probing = false
+		t.mu.Unlock()
+	}
+}
+
+func (t *breakerTransport) failed(req *http.Request, probe bool, reason string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if probe {
+		t.probing = false
+	}
+	t.failures++
+	t.lastErr = reason
+	if t.failures >= t.threshold {
+		if t.failures == t.threshold {
+			tflog.Warn(req.Context(), "API keeps failing, not sending further requests for a while", map[string]interface{}{
+				"failures": t.failures,
+				"error":    reason,
+				"cooldown": t.cooldown.String(),
+			})
+		}
+		t.openedAt = time.Now()
+	}
+}
+
+func (t *breakerTransport) succeeded() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.failures = 0
+	t.probing = false
+}
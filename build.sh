@@ -1,4 +1,6 @@
-GOOS=darwin GOARCH=arm64 go build -o bin/terraform-provider-faxter_v0.1.0_darwin_arm64
-GOOS=darwin GOARCH=amd64 go build -o bin/terraform-provider-faxter_v0.1.0_darwin_amd64
-GOOS=windows GOARCH=amd64 go build -o bin/terraform-provider-faxter_v0.1.0_windows_amd64.exe
-GOOS=linux GOARCH=amd64 go build -o bin/terraform-provider-faxter_v0.1.0_linux_amd64 
\ No newline at end of file
+VERSION=0.1.0
+LDFLAGS="-X main.version=${VERSION}"
+GOOS=darwin GOARCH=arm64 go build -ldflags "${LDFLAGS}" -o bin/terraform-provider-faxter_v${VERSION}_darwin_arm64
+GOOS=darwin GOARCH=amd64 go build -ldflags "${LDFLAGS}" -o bin/terraform-provider-faxter_v${VERSION}_darwin_amd64
+GOOS=windows GOARCH=amd64 go build -ldflags "${LDFLAGS}" -o bin/terraform-provider-faxter_v${VERSION}_windows_amd64.exe
+GOOS=linux GOARCH=amd64 go build -ldflags "${LDFLAGS}" -o bin/terraform-provider-faxter_v${VERSION}_linux_amd64
\ No newline at end of file
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// responseCache keeps successful GET responses for a short time so data
+// sources read many times during one plan or apply hit the API only once.
+type responseCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	status     string
+	statusCode int
+	header     http.Header
+	body       []byte
+	expires    time.Time
+}
+
+func newResponseCache(ttl time.Duration) *responseCache {
+	return &responseCache{
+		ttl:     ttl,
+		entries: make(map[string]cacheEntry),
+	}
+}
+
+// doCached performs req, serving it from the response cache when caching is
+// enabled and an unexpired entry exists. Only GET requests answered with 200
+// are cached; everything else goes straight to the API.
+func (c *Client) doCached(req *http.Request) (*http.Response, error) {
+	if c.cache == nil || req.Method != http.MethodGet {
+		return c.httpClient.Do(req)
+	}
+
+	key := req.Method + " " + req.URL.String()
+
+	c.cache.mu.Lock()
+	entry, ok := c.cache.entries[key]
+	if ok && time.Now().After(entry.expires) {
+		delete(c.cache.entries, key)
+		ok = false
+	}
+	c.cache.mu.Unlock()
+	if ok {
+		return entry.response(req), nil
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	entry = cacheEntry{
+		status:     resp.Status,
+		statusCode: resp.StatusCode,
+		header:     resp.Header.Clone(),
+		body:       body,
+		expires:    time.Now().Add(c.cache.ttl),
+	}
+	c.cache.mu.Lock()
+	c.cache.entries[key] = entry
+	c.cache.mu.Unlock()
+
+	return entry.response(req), nil
+}
+
+func (e cacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        e.status,
+		StatusCode:    e.statusCode,
+		Header:        e.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
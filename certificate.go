@@ -0,0 +1,37 @@
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"time"
+)
+
+// hashSecret is a StateFunc for write-only secrets such as private keys. The
+// value is sent to the API on create and update, but only its SHA-256 is kept
+// in state, which is enough to detect a changed secret in configuration.
+func hashSecret(v interface{}) string {
+	s, ok := v.(string)
+	if !ok || s == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// certificateInfo returns the SHA-256 fingerprint and expiry (RFC 3339) of
+// the first certificate in a PEM chain.
+func certificateInfo(chain string) (string, string, error) {
+	block, _ := pem.Decode([]byte(chain))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return "", "", fmt.Errorf("no PEM-encoded certificate found")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return "", "", err
+	}
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:]), cert.NotAfter.UTC().Format(time.RFC3339), nil
+}
@@ -1,31 +1,136 @@
 package main
 
 import (
-  "fmt"
+  "context"
+  "errors"
   "net/http"
+  "time"
+
+  "github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
 )
 
+// errNotFound is wrapped by lookups when the API reports the object missing.
+var errNotFound = errors.New("not found")
+
 type Client struct {
-  baseURL string
-  token   string
+  // Resources call the API through api, which is rest unless a unit test
+  // substitutes a fake. rest also builds the requests sent directly, and
+  // httpClient is shorthand for rest.HTTPClient.
+  api        FaxterAPI
+  rest       *faxter.Client
   httpClient *http.Client
+
+  // Cancelled when Terraform asks the provider to stop; see withStop.
+  stop context.Context
+
+  // Shared by all servers waiting to come online, see statusPoller.
+  serverStatus *statusPoller
+
+  // Optional cache of GET responses used by data sources; nil when disabled.
+  cache *responseCache
+
+  // Fallbacks used by resources when the corresponding attribute is unset.
+  defaultFlavor   string
+  defaultImage    string
+  defaultNetworks []string
+
+  // Project used by resources that do not set one, and whether resources may
+  // target any other project.
+  defaultProject string
+  enforceProject bool
+
+  // When set, project references are checked against the API at plan time.
+  validateProjects bool
+
+  // When set, resource writes are refused; see guardWrites.
+  readOnly bool
+
+  // When set, reads also check that the objects a resource refers to still
+  // exist and warn about dangling references.
+  deepRefresh bool
+
+  // When set, operations and API calls are recorded as spans; see
+  // withTracing.
+  tracing bool
+
+  // When set, faxter_project renames are sent to the API instead of forcing
+  // a replacement.
+  allowProjectRename bool
+
+  // How long to keep retrying creates that fail because the project's
+  // floating IP pool is exhausted.
+  floatingIPWait time.Duration
+
+  // Version and optional features reported by the API at configure time;
+  // capabilities is nil when the API does not report them. See supports.
+  apiVersion   string
+  capabilities map[string]bool
+
+  // Consecutive "error" polls tolerated before a server create fails.
+  errorRetries int
+
+  // Region served by api's base URL, and clients for the provider's other
+  // endpoints keyed by region; see inRegion.
+  region  string
+  regions map[string]*Client
+
+  // Intervals between status polls while waiting on a resource; see
+  // pollDelay.
+  pollSchedule []time.Duration
 }
 
 func NewClient(baseURL, token string) *Client {
-  return &Client{
-    baseURL: baseURL,
-    token: token,
-    httpClient: &http.Client{},
+  rest := faxter.NewClient(baseURL, token)
+  c := &Client{
+    api: rest,
+    rest: rest,
+    httpClient: rest.HTTPClient,
+    stop: context.Background(),
+    errorRetries: defaultErrorRetries,
   }
+  c.setPollSchedule(defaultPollSchedule)
+  return c
 }
 
-func (c *Client) newRequest(method, path string) (*http.Request, error) {
-  url := fmt.Sprintf("%s%s", c.baseURL, path)
-  req, err := http.NewRequest(method, url, nil)
+// setPollSchedule sets the intervals used while waiting on resources. The
+// shared poller wakes at the first interval.
+func (c *Client) setPollSchedule(schedule []time.Duration) {
+  c.pollSchedule = schedule
+  c.serverStatus = newStatusPoller(c, schedule[0])
+}
+
+// newRequest builds a request for path that is cancelled along with ctx.
+func (c *Client) newRequest(ctx context.Context, method, path string) (*http.Request, error) {
+  req, err := c.rest.NewRequest(method, path)
   if err != nil {
     return nil, err
   }
-  req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.token))
-  req.Header.Set("Content-Type", "application/json")
-  return req, nil
-}
\ No newline at end of file
+  return req.WithContext(ctx), nil
+}
+
+// withHeaders returns a copy of the client whose requests also carry
+// headers, replacing any of the provider's extra_headers with the same name.
+func (c *Client) withHeaders(headers http.Header) *Client {
+  merged := http.Header{}
+  for k, v := range c.rest.Headers {
+    merged[k] = v
+  }
+  for k, v := range headers {
+    merged[k] = v
+  }
+
+  rest := *c.rest
+  rest.Headers = merged
+  return c.withREST(&rest)
+}
+
+// withREST returns a copy of the client sending its requests through rest.
+// A fake standing in for the API is kept.
+func (c *Client) withREST(rest *faxter.Client) *Client {
+  scoped := *c
+  if c.api == FaxterAPI(c.rest) {
+    scoped.api = rest
+  }
+  scoped.rest = rest
+  return &scoped
+}
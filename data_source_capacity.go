@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceCapacity() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceCapacityRead,
+
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Project whose quota and placement rules apply. Defaults to the provider's project.",
+			},
+			"flavor": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only report availability of this flavor.",
+			},
+			"availability_zones": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Free capacity per availability zone. Empty when the API does not publish capacity.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"vcpus_available": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"ram_available_gb": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"flavors": {
+							Type:        schema.TypeMap,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeInt},
+							Description: "Number of servers of each flavor that can still be placed in the zone.",
+						},
+					},
+				},
+			},
+			"recommended_availability_zone": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Zone with the most room for flavor (or the most free vCPUs when flavor is unset). Empty when no zone has room.",
+			},
+		},
+	}
+}
+
+func dataSourceCapacityRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+	var diags diag.Diagnostics
+
+	project := d.Get("project").(string)
+	if project == "" {
+		project = c.defaultProject
+	}
+	flavor := d.Get("flavor").(string)
+
+	req, err := c.newRequest(ctx, "GET", faxter.CapacityPath(project, flavor))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	resp, err := c.doCached(req)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer resp.Body.Close()
+
+	var capacity faxter.CapacityResponse
+	switch resp.StatusCode {
+	case http.StatusOK:
+		if err := c.rest.DecodeResponse(resp, &capacity); err != nil {
+			return diag.FromErr(err)
+		}
+	case http.StatusNotFound, http.StatusNotImplemented:
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Capacity information is not available",
+			Detail:   fmt.Sprintf("The Faxter API does not publish capacity (%s), so availability_zones is empty.", resp.Status),
+		})
+	default:
+		return apiErrorDiag("Failed to read capacity", faxter.NewError(resp))
+	}
+
+	zones := make([]interface{}, 0, len(capacity.AvailabilityZones))
+	recommended, best := "", 0
+	for _, az := range capacity.AvailabilityZones {
+		flavors := make(map[string]interface{}, len(az.Flavors))
+		for _, f := range az.Flavors {
+			flavors[f.Name] = f.Available
+		}
+		zones = append(zones, map[string]interface{}{
+			"name":             az.Name,
+			"vcpus_available":  az.VCPUsAvailable,
+			"ram_available_gb": az.RAMAvailableGB,
+			"flavors":          flavors,
+		})
+
+		room := az.VCPUsAvailable
+		if flavor != "" {
+			room = 0
+			for _, f := range az.Flavors {
+				if f.Name == flavor {
+					room = f.Available
+				}
+			}
+		}
+		if room > best {
+			recommended, best = az.Name, room
+		}
+	}
+
+	if err := d.Set("availability_zones", zones); err != nil {
+		return diag.Errorf("Error setting availability_zones: %s", err)
+	}
+	if err := d.Set("recommended_availability_zone", recommended); err != nil {
+		return diag.Errorf("Error setting recommended_availability_zone: %s", err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", project, flavor))
+	return diags
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// dataSourceInventory renders a project's servers as an Ansible inventory,
+// grouped by their metadata, so that configuration management can pick up
+// what Terraform provisioned without a dynamic inventory script.
+func dataSourceInventory() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceInventoryRead,
+
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Project whose servers are listed. Defaults to the provider's project.",
+			},
+			"tag": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only include servers with this metadata key, or with key=value, e.g. \"env=staging\".",
+			},
+			"group_by": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Metadata keys to group servers by. A server whose role is \"web\" joins the group role_web.",
+			},
+			"groups": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name":  {Type: schema.TypeString, Computed: true},
+						"hosts": {Type: schema.TypeList, Computed: true, Elem: &schema.Schema{Type: schema.TypeString}},
+					},
+				},
+			},
+			"hosts": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {Type: schema.TypeString, Computed: true},
+						"ansible_host": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "First IP address of the server.",
+						},
+						"ip_addresses": {Type: schema.TypeList, Computed: true, Elem: &schema.Schema{Type: schema.TypeString}},
+						"key_name":     {Type: schema.TypeString, Computed: true},
+						"metadata":     {Type: schema.TypeMap, Computed: true, Elem: &schema.Schema{Type: schema.TypeString}},
+					},
+				},
+			},
+			"inventory_json": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The inventory in Ansible's JSON format, with hostvars under _meta. It is also valid YAML, so it can be written to a file with local_file and passed to ansible-playbook -i.",
+			},
+		},
+	}
+}
+
+// inventoryGroupInvalid matches the characters Ansible doesn't allow in group
+// names.
+var inventoryGroupInvalid = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// inventoryGroupName makes a metadata key and value into a group name.
+func inventoryGroupName(key, value string) string {
+	return inventoryGroupInvalid.ReplaceAllString(key+"_"+value, "_")
+}
+
+// matchesTag reports whether metadata has the key of tag and, if tag is
+// key=value, that value.
+func matchesTag(metadata map[string]string, tag string) bool {
+	key, value, hasValue := strings.Cut(tag, "=")
+	v, ok := metadata[key]
+	return ok && (!hasValue || v == value)
+}
+
+func dataSourceInventoryRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+	var diags diag.Diagnostics
+
+	project := d.Get("project").(string)
+	if project == "" {
+		project = c.defaultProject
+	}
+	tag := d.Get("tag").(string)
+	groupBy := expandStringList(d.Get("group_by").([]interface{}))
+
+	servers, err := listServers(ctx, c, project)
+	if err != nil {
+		return apiErrorDiag("Failed to list servers", err)
+	}
+	sort.Slice(servers, func(i, j int) bool { return servers[i].Name < servers[j].Name })
+
+	var hosts []interface{}
+	allHosts := []string{}
+	hostvars := map[string]interface{}{}
+	members := map[string][]string{}
+	for _, server := range servers {
+		if tag != "" && !matchesTag(server.Properties.Metadata, tag) {
+			continue
+		}
+
+		vars := map[string]interface{}{
+			"ip_addresses": server.Properties.IPAddresses,
+			"key_name":     server.Properties.KeyName,
+			"metadata":     server.Properties.Metadata,
+		}
+		ansibleHost := ""
+		if len(server.Properties.IPAddresses) > 0 {
+			ansibleHost = server.Properties.IPAddresses[0]
+			vars["ansible_host"] = ansibleHost
+		}
+		hostvars[server.Name] = vars
+		allHosts = append(allHosts, server.Name)
+		hosts = append(hosts, map[string]interface{}{
+			"name":         server.Name,
+			"ansible_host": ansibleHost,
+			"ip_addresses": server.Properties.IPAddresses,
+			"key_name":     server.Properties.KeyName,
+			"metadata":     server.Properties.Metadata,
+		})
+
+		for _, key := range groupBy {
+			if value, ok := server.Properties.Metadata[key]; ok {
+				group := inventoryGroupName(key, value)
+				members[group] = append(members[group], server.Name)
+			}
+		}
+	}
+
+	names := make([]string, 0, len(members))
+	for name := range members {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var groups []interface{}
+	inventory := map[string]interface{}{
+		"_meta": map[string]interface{}{"hostvars": hostvars},
+		"all":   map[string]interface{}{"hosts": allHosts, "children": names},
+	}
+	for _, name := range names {
+		groups = append(groups, map[string]interface{}{"name": name, "hosts": members[name]})
+		inventory[name] = map[string]interface{}{"hosts": members[name]}
+	}
+	inventoryJSON, err := json.MarshalIndent(inventory, "", "  ")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	values := map[string]interface{}{
+		"groups":         groups,
+		"hosts":          hosts,
+		"inventory_json": string(inventoryJSON),
+	}
+	for k, v := range values {
+		if err := d.Set(k, v); err != nil {
+			return diag.Errorf("Error setting %s: %s", k, err)
+		}
+	}
+
+	d.SetId(project + "/" + tag)
+	return diags
+}
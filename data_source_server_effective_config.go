@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceServerEffectiveConfig() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceServerEffectiveConfigRead,
+
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Project of the server. Defaults to the provider's project.",
+			},
+			"server": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the server.",
+			},
+			"flavor": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name":    {Type: schema.TypeString, Computed: true},
+						"vcpus":   {Type: schema.TypeInt, Computed: true},
+						"ram_gb":  {Type: schema.TypeInt, Computed: true},
+						"disk_gb": {Type: schema.TypeInt, Computed: true},
+					},
+				},
+			},
+			"image": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {Type: schema.TypeString, Computed: true},
+						"id":   {Type: schema.TypeString, Computed: true},
+						"build": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Build the server was created from, which stays the same when the image name is pointed at a newer build.",
+						},
+					},
+				},
+			},
+			"security_groups": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Security groups in effect, including those the platform added by default.",
+			},
+			"networks": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"availability_zone": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"key_name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"applied_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "When the configuration was last applied, in RFC 3339 format.",
+			},
+			"config_json": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The configuration as returned by the API, including fields not exposed as attributes, for archiving as evidence.",
+			},
+		},
+	}
+}
+
+func dataSourceServerEffectiveConfigRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+	var diags diag.Diagnostics
+
+	project := d.Get("project").(string)
+	if project == "" {
+		project = c.defaultProject
+	}
+	server := d.Get("server").(string)
+
+	path := fmt.Sprintf("/servers/%s/effective_config?project_name=%s", url.PathEscape(server), url.QueryEscape(project))
+	req, err := c.newRequest(ctx, "GET", path)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	resp, err := c.doCached(req)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return diag.Errorf("Server '%s' not found in project '%s'", server, project)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return apiErrorDiag("Failed to read server effective configuration", faxter.NewError(resp))
+	}
+
+	var raw json.RawMessage
+	if err := faxter.DecodeResponse(resp, &raw); err != nil {
+		return diag.FromErr(err)
+	}
+	var config faxter.ServerEffectiveConfigResponse
+	if err := c.rest.Unmarshal(raw, &config); err != nil {
+		return diag.FromErr(err)
+	}
+
+	values := map[string]interface{}{
+		"flavor": []interface{}{map[string]interface{}{
+			"name":    config.Flavor.Name,
+			"vcpus":   config.Flavor.VCPUs,
+			"ram_gb":  config.Flavor.RAMGB,
+			"disk_gb": config.Flavor.DiskGB,
+		}},
+		"image": []interface{}{map[string]interface{}{
+			"name":  config.Image.Name,
+			"id":    config.Image.ID,
+			"build": config.Image.Build,
+		}},
+		"security_groups":   config.SecurityGroups,
+		"networks":          config.Networks,
+		"availability_zone": config.AvailabilityZone,
+		"key_name":          config.KeyName,
+		"applied_at":        config.AppliedAt,
+		"config_json":       string(raw),
+	}
+	for k, v := range values {
+		if err := d.Set(k, v); err != nil {
+			return diag.Errorf("Error setting %s: %s", k, err)
+		}
+	}
+
+	d.SetId(project + "/" + server)
+	return diags
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceServerMetrics() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceServerMetricsRead,
+
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Project of the server. Defaults to the provider's project.",
+			},
+			"server": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the server to fetch metrics for.",
+			},
+			"window": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "5m",
+				ValidateFunc: validateDuration,
+				Description:  "Aggregation window ending now, as a Go duration such as 5m or 1h.",
+			},
+			"statistic": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "avg",
+				ValidateFunc: validation.StringInSlice([]string{"avg", "min", "max", "p95"}, false),
+				Description:  "Statistic applied over the window: avg, min, max or p95.",
+			},
+			"cpu_percent": {
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+			"memory_percent": {
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+			"disk_read_bytes_per_sec": {
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+			"disk_write_bytes_per_sec": {
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+			"network_rx_bytes_per_sec": {
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+			"network_tx_bytes_per_sec": {
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+			"sample_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of samples the statistic was computed from; 0 means no data in the window.",
+			},
+		},
+	}
+}
+
+func dataSourceServerMetricsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+	var diags diag.Diagnostics
+
+	project := d.Get("project").(string)
+	if project == "" {
+		project = c.defaultProject
+	}
+	server := d.Get("server").(string)
+	window := d.Get("window").(string)
+	statistic := d.Get("statistic").(string)
+
+	query := url.Values{}
+	query.Set("project_name", project)
+	query.Set("window", window)
+	query.Set("statistic", statistic)
+	path := fmt.Sprintf("/servers/%s/metrics?%s", url.PathEscape(server), query.Encode())
+
+	req, err := c.newRequest(ctx, "GET", path)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	resp, err := c.doCached(req)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return diag.Errorf("Server '%s' not found in project '%s'", server, project)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return apiErrorDiag("Failed to read server metrics", faxter.NewError(resp))
+	}
+
+	var metrics faxter.ServerMetricsResponse
+	if err := c.rest.DecodeResponse(resp, &metrics); err != nil {
+		return diag.FromErr(err)
+	}
+
+	values := map[string]interface{}{
+		"cpu_percent":              metrics.CPUPercent,
+		"memory_percent":           metrics.MemoryPercent,
+		"disk_read_bytes_per_sec":  metrics.DiskReadBytesPerSec,
+		"disk_write_bytes_per_sec": metrics.DiskWriteBytesPerSec,
+		"network_rx_bytes_per_sec": metrics.NetworkRxBytesPerSec,
+		"network_tx_bytes_per_sec": metrics.NetworkTxBytesPerSec,
+		"sample_count":             metrics.SampleCount,
+	}
+	for k, v := range values {
+		if err := d.Set(k, v); err != nil {
+			return diag.Errorf("Error setting %s: %s", k, err)
+		}
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s/%s/%s", project, server, window, statistic))
+	return diags
+}
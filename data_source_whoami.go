@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceWhoami() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceWhoamiRead,
+
+		Schema: map[string]*schema.Schema{
+			"account_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "ID of the account the provider token belongs to.",
+			},
+			"username": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"email": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"scopes": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Scopes granted to the token.",
+			},
+			"default_project": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"expires_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "RFC 3339 time at which the token expires; empty if it does not expire.",
+			},
+		},
+	}
+}
+
+func dataSourceWhoamiRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+	var diags diag.Diagnostics
+
+	req, err := c.newRequest(ctx, "GET", "/whoami")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	resp, err := c.doCached(req)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return apiErrorDiag("Failed to read account information", faxter.NewError(resp))
+	}
+
+	var whoami faxter.WhoamiResponse
+	if err := c.rest.DecodeResponse(resp, &whoami); err != nil {
+		return diag.FromErr(err)
+	}
+
+	values := map[string]interface{}{
+		"account_id":      whoami.AccountID,
+		"username":        whoami.Username,
+		"email":           whoami.Email,
+		"scopes":          whoami.Scopes,
+		"default_project": whoami.DefaultProject,
+		"expires_at":      whoami.ExpiresAt,
+	}
+	for k, v := range values {
+		if err := d.Set(k, v); err != nil {
+			return diag.Errorf("Error setting %s: %s", k, err)
+		}
+	}
+
+	d.SetId(whoami.AccountID)
+	return diags
+}
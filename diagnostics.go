@@ -0,0 +1,57 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceGone handles an update or delete that got a 404 because the
+// resource was removed outside Terraform between plan and apply. It drops
+// the resource from state and warns instead of failing the whole apply.
+func resourceGone(d *schema.ResourceData, kind string) diag.Diagnostics {
+	id := d.Id()
+	d.SetId("")
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("%s %q no longer exists", kind, id),
+		Detail:   fmt.Sprintf("The %s was deleted outside of Terraform and has been removed from state. It will be recreated on the next apply if it is still in the configuration.", kind),
+	}}
+}
+
+// apiErrorDiag reports err under summary, e.g. "Failed to create server".
+// When err comes from the API, the detail lists the HTTP status, the API's
+// error code and the request ID to quote to Faxter support. A request held
+// back by the circuit breaker is reported as the API being unavailable.
+func apiErrorDiag(summary string, err error) diag.Diagnostics {
+	var open *circuitOpenError
+	if errors.As(err, &open) {
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  summary + ": the Faxter API is unavailable",
+			Detail:   open.Error() + ". See circuit_breaker_threshold.",
+		}}
+	}
+
+	var apiErr *faxter.Error
+	if !errors.As(err, &apiErr) {
+		return diag.Errorf("%s: %s", summary, err)
+	}
+
+	detail := []string{"HTTP status: " + apiErr.Status}
+	if apiErr.Code != "" {
+		detail = append(detail, "Error code: "+apiErr.Code)
+	}
+	if apiErr.RequestID != "" {
+		detail = append(detail, "Request ID: "+apiErr.RequestID+" (include this when contacting Faxter support)")
+	}
+	return diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  fmt.Sprintf("%s: %s", summary, err),
+		Detail:   strings.Join(detail, "\n"),
+	}}
+}
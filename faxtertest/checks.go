@@ -0,0 +1,101 @@
+package faxtertest
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// CheckExists returns a test check that fails unless the resource at addr
+// (e.g. "faxter_server.web") can be read back from the API at baseURL. The
+// returned function satisfies resource.TestCheckFunc. Only the resource types
+// the mock supports can be checked; see the package documentation.
+func CheckExists(baseURL, token, addr string) func(*terraform.State) error {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[addr]
+		if !ok {
+			return fmt.Errorf("resource %s not found in state", addr)
+		}
+		if rs.Primary == nil || rs.Primary.ID == "" {
+			return fmt.Errorf("resource %s has no ID set", addr)
+		}
+
+		found, err := lookup(baseURL, token, rs)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("resource %s (%s) does not exist in the API", addr, rs.Primary.ID)
+		}
+		return nil
+	}
+}
+
+// CheckDestroy returns a test check that fails if any resource of the given
+// type recorded in state can still be read back from the API at baseURL. It
+// is intended for resource.TestCase.CheckDestroy.
+func CheckDestroy(baseURL, token, resourceType string) func(*terraform.State) error {
+	return func(s *terraform.State) error {
+		for addr, rs := range s.RootModule().Resources {
+			if rs.Type != resourceType || rs.Primary == nil {
+				continue
+			}
+
+			found, err := lookup(baseURL, token, rs)
+			if err != nil {
+				return err
+			}
+			if found {
+				return fmt.Errorf("resource %s (%s) still exists", addr, rs.Primary.ID)
+			}
+		}
+		return nil
+	}
+}
+
+// CheckExists is CheckExists bound to the mock server's URL and token.
+func (s *Server) CheckExists(addr string) func(*terraform.State) error {
+	return CheckExists(s.URL, s.Token, addr)
+}
+
+// CheckDestroy is CheckDestroy bound to the mock server's URL and token.
+func (s *Server) CheckDestroy(resourceType string) func(*terraform.State) error {
+	return CheckDestroy(s.URL, s.Token, resourceType)
+}
+
+// lookup issues the same GET the provider uses on read and reports whether
+// the object exists.
+func lookup(baseURL, token string, rs *terraform.ResourceState) (bool, error) {
+	collection, ok := collections[rs.Type]
+	if !ok {
+		return false, fmt.Errorf("resource type %q is not supported by faxtertest", rs.Type)
+	}
+
+	path := fmt.Sprintf("%s/%s/%s", baseURL, collection, url.PathEscape(rs.Primary.ID))
+	if !unscoped[collection] {
+		path += "?project_name=" + url.QueryEscape(rs.Primary.Attributes["project"])
+	}
+
+	req, err := http.NewRequest("GET", path, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("failed to read %s %s: %s", rs.Type, rs.Primary.ID, resp.Status)
+	}
+}
@@ -0,0 +1,1056 @@
+package faxtertest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+)
+
+// FakeAPI is an in-memory implementation of the client methods the
+// provider's resources call, for unit tests of their create, read, update
+// and delete logic without an API or HTTP server. Objects are stored as the
+// JSON the API would return, so expand and flatten functions see the same
+// shapes they do in production.
+type FakeAPI struct {
+	// Errors makes the named method, e.g. "GetServer", fail with the error
+	// instead of touching the store. Use APIError for API statuses.
+	Errors map[string]error
+
+	// Statuses sets the status new objects of a collection get, e.g.
+	// {"servers": "building"} to exercise polling. Servers are otherwise
+	// created "online" and other objects "active".
+	Statuses map[string]string
+
+	mu      sync.Mutex
+	objects map[string]map[string]interface{}
+	calls   []string
+	nextID  int
+}
+
+// NewFakeAPI returns an empty FakeAPI.
+func NewFakeAPI() *FakeAPI {
+	return &FakeAPI{
+		Errors:   map[string]error{},
+		Statuses: map[string]string{},
+		objects:  map[string]map[string]interface{}{},
+	}
+}
+
+// APIError returns the error the client reports for an API response with
+// status and a "detail" message.
+func APIError(status int, detail string) *faxter.Error {
+	body, _ := json.Marshal(map[string]string{"detail": detail})
+	return &faxter.Error{
+		StatusCode: status,
+		Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Body:       string(body),
+	}
+}
+
+// Calls returns the names of the methods called so far, in order.
+func (f *FakeAPI) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.calls...)
+}
+
+// Put stores obj, encoded as JSON, as the named object of collection. Tests
+// use it to seed objects, including ones only the API creates such as
+// "server_passwords", "server_events" (a list per server) and
+// "project_public_keys" (keyed by project).
+func (f *FakeAPI) Put(collection, project, name string, obj interface{}) error {
+	var value interface{}
+	if err := fromJSONValue(obj, &value); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.store(collection)[key(collection, project, name)] = value
+	return nil
+}
+
+// Get decodes the named object of collection into out and reports whether
+// it exists.
+func (f *FakeAPI) Get(collection, project, name string, out interface{}) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	obj, ok := f.store(collection)[key(collection, project, name)]
+	if !ok {
+		return false, nil
+	}
+	return true, fromJSONValue(obj, out)
+}
+
+// SetStatus changes the status of a stored object, e.g. to bring a server
+// a test is waiting on online.
+func (f *FakeAPI) SetStatus(collection, project, name, status string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	obj, ok := f.store(collection)[key(collection, project, name)].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%s %s/%s not found", collection, project, name)
+	}
+	obj["status"] = status
+	return nil
+}
+
+// Do answers GET requests for object paths, such as those built by
+// faxter.ObjectPath, from the store. Other requests are not supported.
+func (f *FakeAPI) Do(ctx context.Context, method, path string, in, out interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("Do"); err != nil {
+		return err
+	}
+
+	u, err := url.Parse(path)
+	if err != nil {
+		return err
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if method != http.MethodGet || len(parts) != 2 {
+		return fmt.Errorf("FakeAPI does not support %s %s", method, path)
+	}
+	name, err := url.PathUnescape(parts[1])
+	if err != nil {
+		return err
+	}
+	obj, ok := f.store(parts[0])[key(parts[0], u.Query().Get("project_name"), name)]
+	if !ok {
+		return APIError(http.StatusNotFound, "not found")
+	}
+	if out == nil {
+		return nil
+	}
+	return fromJSONValue(obj, out)
+}
+
+func (f *FakeAPI) CreateProject(ctx context.Context, name string) error {
+	return f.create("CreateProject", "projects", "", name, map[string]interface{}{"name": name}, nil)
+}
+
+func (f *FakeAPI) ProjectExists(ctx context.Context, name string) (bool, error) {
+	err := f.get("ProjectExists", "projects", "", name, nil)
+	if faxter.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (f *FakeAPI) RenameProject(ctx context.Context, name, newName string) error {
+	return f.update("RenameProject", "projects", "", name, map[string]interface{}{"name": newName}, false)
+}
+
+func (f *FakeAPI) DeleteProject(ctx context.Context, name string) error {
+	return f.remove("DeleteProject", "projects", "", name)
+}
+
+func (f *FakeAPI) GetProjectPublicKey(ctx context.Context, project string) (*faxter.ProjectPublicKeyResponse, error) {
+	var key faxter.ProjectPublicKeyResponse
+	if err := f.get("GetProjectPublicKey", "project_public_keys", project, project, &key); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (f *FakeAPI) CreateServer(ctx context.Context, req *faxter.ServerCreateRequest) ([]faxter.ResourceResponse, error) {
+	var server faxter.ResourceResponse
+	obj, err := f.resource("servers", req.Name, req)
+	if err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	obj["properties"].(map[string]interface{})["ip_addresses"] = []interface{}{fmt.Sprintf("10.0.0.%d", f.nextID%250+2)}
+	f.mu.Unlock()
+	if err := f.create("CreateServer", "servers", req.Project, req.Name, obj, &server); err != nil {
+		return nil, err
+	}
+	return []faxter.ResourceResponse{server}, nil
+}
+
+func (f *FakeAPI) GetServer(ctx context.Context, project, name string) (*faxter.ResourceResponse, error) {
+	var server faxter.ResourceResponse
+	if err := f.get("GetServer", "servers", project, name, &server); err != nil {
+		return nil, err
+	}
+	return &server, nil
+}
+
+func (f *FakeAPI) ListServers(ctx context.Context, project string) ([]faxter.ResourceResponse, error) {
+	var servers []faxter.ResourceResponse
+	if err := f.list("ListServers", "servers", project+"/", &servers); err != nil {
+		return nil, err
+	}
+	return servers, nil
+}
+
+func (f *FakeAPI) UpdateServer(ctx context.Context, project, name string, req *faxter.ServerUpdateRequest) error {
+	return f.update("UpdateServer", "servers", project, name, req, true)
+}
+
+func (f *FakeAPI) DeleteServer(ctx context.Context, project, name string) error {
+	return f.remove("DeleteServer", "servers", project, name)
+}
+
+// RebuildServer records the new image; the server stays online.
+func (f *FakeAPI) RebuildServer(ctx context.Context, project, name string, req *faxter.ServerRebuildRequest) error {
+	return f.update("RebuildServer", "servers", project, name, req, true)
+}
+
+// AttachVolume adds the volume to the server's volumes and marks it
+// "in-use" at once.
+func (f *FakeAPI) AttachVolume(ctx context.Context, project, server, volume string) error {
+	return f.editServerVolumes("AttachVolume", project, server, volume, "in-use", func(volumes []string) ([]string, bool) {
+		for _, v := range volumes {
+			if v == volume {
+				return volumes, false
+			}
+		}
+		return append(volumes, volume), true
+	})
+}
+
+// DetachVolume removes the volume from the server's volumes and marks it
+// "available" at once.
+func (f *FakeAPI) DetachVolume(ctx context.Context, project, server, volume string) error {
+	return f.editServerVolumes("DetachVolume", project, server, volume, "available", func(volumes []string) ([]string, bool) {
+		for i, v := range volumes {
+			if v == volume {
+				return append(volumes[:i], volumes[i+1:]...), true
+			}
+		}
+		return volumes, false
+	})
+}
+
+func (f *FakeAPI) ListServerEvents(ctx context.Context, project, name string) ([]faxter.ServerEvent, error) {
+	var events []faxter.ServerEvent
+	err := f.get("ListServerEvents", "server_events", project, name, &events)
+	if faxter.IsNotFound(err) {
+		return nil, nil
+	}
+	return events, err
+}
+
+func (f *FakeAPI) GetServerPassword(ctx context.Context, project, name string) (*faxter.ServerPasswordResponse, error) {
+	var password faxter.ServerPasswordResponse
+	if err := f.get("GetServerPassword", "server_passwords", project, name, &password); err != nil {
+		return nil, err
+	}
+	return &password, nil
+}
+
+// StartExec records a command that completes at once with exit code 0. Put
+// an "execs" object under "<server>/<id>" to script another outcome.
+func (f *FakeAPI) StartExec(ctx context.Context, project, server string, req *faxter.ExecRequest) (*faxter.ExecResponse, error) {
+	id := f.newID("exec")
+	exec := map[string]interface{}{"id": id, "status": "completed", "exit_code": 0, "output": ""}
+	var resp faxter.ExecResponse
+	if err := f.create("StartExec", "execs", project, server+"/"+id, exec, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (f *FakeAPI) GetExec(ctx context.Context, project, server, id string) (*faxter.ExecResponse, error) {
+	var exec faxter.ExecResponse
+	if err := f.get("GetExec", "execs", project, server+"/"+id, &exec); err != nil {
+		return nil, err
+	}
+	return &exec, nil
+}
+
+func (f *FakeAPI) CreateSSHKey(ctx context.Context, req *faxter.SSHKeyCreateRequest) (*faxter.ResourceResponse, error) {
+	return f.createResource("CreateSSHKey", "ssh_keys", "", req.Name, req)
+}
+
+func (f *FakeAPI) GetSSHKey(ctx context.Context, name string) (*faxter.ResourceResponse, error) {
+	return f.getResource("GetSSHKey", "ssh_keys", "", name)
+}
+
+func (f *FakeAPI) UpdateSSHKey(ctx context.Context, name string, req *faxter.SSHKeyUpdateRequest) error {
+	return f.update("UpdateSSHKey", "ssh_keys", "", name, req, true)
+}
+
+func (f *FakeAPI) DeleteSSHKey(ctx context.Context, name string) error {
+	return f.remove("DeleteSSHKey", "ssh_keys", "", name)
+}
+
+func (f *FakeAPI) CreateNetwork(ctx context.Context, req *faxter.NetworkCreateRequest) (*faxter.ResourceResponse, error) {
+	return f.createResource("CreateNetwork", "networks", req.Project, req.Name, req)
+}
+
+func (f *FakeAPI) GetNetwork(ctx context.Context, project, name string) (*faxter.NetworkResponse, error) {
+	var network faxter.NetworkResponse
+	if err := f.get("GetNetwork", "networks", project, name, &network); err != nil {
+		return nil, err
+	}
+	return &network, nil
+}
+
+func (f *FakeAPI) UpdateNetwork(ctx context.Context, project, name string, req *faxter.NetworkCreateRequest) error {
+	return f.update("UpdateNetwork", "networks", project, name, req, true)
+}
+
+func (f *FakeAPI) DeleteNetwork(ctx context.Context, project, name string) error {
+	return f.remove("DeleteNetwork", "networks", project, name)
+}
+
+func (f *FakeAPI) CreateRouter(ctx context.Context, req *faxter.RouterCreateRequest) (*faxter.ResourceResponse, error) {
+	return f.createResource("CreateRouter", "routers", req.Project, req.Name, req)
+}
+
+func (f *FakeAPI) GetRouter(ctx context.Context, project, name string) (*faxter.ResourceResponse, error) {
+	return f.getResource("GetRouter", "routers", project, name)
+}
+
+func (f *FakeAPI) UpdateRouter(ctx context.Context, project, name string, req *faxter.RouterCreateRequest) error {
+	return f.update("UpdateRouter", "routers", project, name, req, true)
+}
+
+func (f *FakeAPI) DeleteRouter(ctx context.Context, project, name string) error {
+	return f.remove("DeleteRouter", "routers", project, name)
+}
+
+func (f *FakeAPI) CreateGatewayService(ctx context.Context, req *faxter.GatewayServiceRequest) error {
+	obj, err := f.flat("gateway_services", req)
+	if err != nil {
+		return err
+	}
+	return f.create("CreateGatewayService", "gateway_services", req.Project, req.Name, obj, nil)
+}
+
+func (f *FakeAPI) GetGatewayService(ctx context.Context, project, name string) (*faxter.GatewayServiceResponse, error) {
+	var gateway faxter.GatewayServiceResponse
+	if err := f.get("GetGatewayService", "gateway_services", project, name, &gateway); err != nil {
+		return nil, err
+	}
+	return &gateway, nil
+}
+
+func (f *FakeAPI) UpdateGatewayService(ctx context.Context, project, name string, req *faxter.GatewayServiceUpdateRequest) error {
+	return f.update("UpdateGatewayService", "gateway_services", project, name, req, false)
+}
+
+func (f *FakeAPI) DeleteGatewayService(ctx context.Context, project, name string) error {
+	return f.remove("DeleteGatewayService", "gateway_services", project, name)
+}
+
+func (f *FakeAPI) CreateQoSPolicy(ctx context.Context, req *faxter.QoSPolicyRequest) error {
+	obj, err := toJSONValue(req)
+	if err != nil {
+		return err
+	}
+	return f.create("CreateQoSPolicy", "qos_policies", req.Project, req.Name, obj, nil)
+}
+
+func (f *FakeAPI) GetQoSPolicy(ctx context.Context, project, name string) (*faxter.QoSPolicyResponse, error) {
+	var policy faxter.QoSPolicyResponse
+	if err := f.get("GetQoSPolicy", "qos_policies", project, name, &policy); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func (f *FakeAPI) UpdateQoSPolicy(ctx context.Context, project, name string, req *faxter.QoSPolicyRequest) error {
+	return f.update("UpdateQoSPolicy", "qos_policies", project, name, req, false)
+}
+
+func (f *FakeAPI) DeleteQoSPolicy(ctx context.Context, project, name string) error {
+	return f.remove("DeleteQoSPolicy", "qos_policies", project, name)
+}
+
+// CreateSecurityGroup also stores the group's rules, so that they can be
+// listed like those added with CreateSecurityGroupRule.
+func (f *FakeAPI) CreateSecurityGroup(ctx context.Context, req *faxter.SecurityGroupCreateRequest) (*faxter.ResourceResponse, error) {
+	group, err := f.createResource("CreateSecurityGroup", "security_groups", req.Project, req.Name, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.replaceRules(req.Project, req.Name, req.Name, req.Rules); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+func (f *FakeAPI) GetSecurityGroup(ctx context.Context, project, name string) (*faxter.ResourceResponse, error) {
+	return f.getResource("GetSecurityGroup", "security_groups", project, name)
+}
+
+// UpdateSecurityGroup replaces the group's rules, as the API does.
+func (f *FakeAPI) UpdateSecurityGroup(ctx context.Context, project, name string, req *faxter.SecurityGroupCreateRequest) error {
+	if err := f.update("UpdateSecurityGroup", "security_groups", project, name, req, true); err != nil {
+		return err
+	}
+	return f.replaceRules(project, name, req.Name, req.Rules)
+}
+
+func (f *FakeAPI) DeleteSecurityGroup(ctx context.Context, project, name string) error {
+	if err := f.remove("DeleteSecurityGroup", "security_groups", project, name); err != nil {
+		return err
+	}
+	return f.replaceRules(project, name, name, nil)
+}
+
+func (f *FakeAPI) CreateSecurityGroupRule(ctx context.Context, project, securityGroup string, req *faxter.SecurityGroupRuleRequest) (*faxter.SecurityGroupRuleResponse, error) {
+	if err := f.get("CreateSecurityGroupRule", "security_groups", project, securityGroup, nil); err != nil {
+		return nil, err
+	}
+	rule, err := f.rule(req)
+	if err != nil {
+		return nil, err
+	}
+	var resp faxter.SecurityGroupRuleResponse
+	if err := f.create("CreateSecurityGroupRule", "security_group_rules", project, securityGroup+"/"+rule["id"].(string), rule, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (f *FakeAPI) GetSecurityGroupRule(ctx context.Context, project, securityGroup, ruleID string) (*faxter.SecurityGroupRuleResponse, error) {
+	var rule faxter.SecurityGroupRuleResponse
+	if err := f.get("GetSecurityGroupRule", "security_group_rules", project, securityGroup+"/"+ruleID, &rule); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (f *FakeAPI) ListSecurityGroupRules(ctx context.Context, project, securityGroup string) ([]faxter.SecurityGroupRuleResponse, error) {
+	if err := f.get("ListSecurityGroupRules", "security_groups", project, securityGroup, nil); err != nil {
+		return nil, err
+	}
+	var rules []faxter.SecurityGroupRuleResponse
+	if err := f.list("ListSecurityGroupRules", "security_group_rules", key("security_group_rules", project, securityGroup+"/"), &rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (f *FakeAPI) DeleteSecurityGroupRule(ctx context.Context, project, securityGroup, ruleID string) error {
+	return f.remove("DeleteSecurityGroupRule", "security_group_rules", project, securityGroup+"/"+ruleID)
+}
+
+func (f *FakeAPI) CreateVolume(ctx context.Context, req *faxter.VolumeCreateRequest) (*faxter.ResourceResponse, error) {
+	return f.createResource("CreateVolume", "volumes", req.Project, req.Name, req)
+}
+
+func (f *FakeAPI) GetVolume(ctx context.Context, project, name string) (*faxter.ResourceResponse, error) {
+	return f.getResource("GetVolume", "volumes", project, name)
+}
+
+func (f *FakeAPI) UpdateVolume(ctx context.Context, project, name string, req *faxter.VolumeUpdateRequest) error {
+	return f.update("UpdateVolume", "volumes", project, name, req, true)
+}
+
+func (f *FakeAPI) DeleteVolume(ctx context.Context, project, name string) error {
+	return f.remove("DeleteVolume", "volumes", project, name)
+}
+
+func (f *FakeAPI) CreateLoadBalancer(ctx context.Context, req *faxter.LoadBalancerCreateRequest) (*faxter.LoadBalancerResponse, error) {
+	obj, err := f.resource("loadbalancers", req.Name, req)
+	if err != nil {
+		return nil, err
+	}
+	var lb faxter.LoadBalancerResponse
+	if err := f.create("CreateLoadBalancer", "loadbalancers", req.Project, req.Name, obj, &lb); err != nil {
+		return nil, err
+	}
+	return &lb, nil
+}
+
+func (f *FakeAPI) GetLoadBalancer(ctx context.Context, project, name string) (*faxter.LoadBalancerResponse, error) {
+	var lb faxter.LoadBalancerResponse
+	if err := f.get("GetLoadBalancer", "loadbalancers", project, name, &lb); err != nil {
+		return nil, err
+	}
+	return &lb, nil
+}
+
+func (f *FakeAPI) UpdateLoadBalancer(ctx context.Context, project, name string, req *faxter.LoadBalancerUpdateRequest) error {
+	return f.update("UpdateLoadBalancer", "loadbalancers", project, name, req, true)
+}
+
+func (f *FakeAPI) DeleteLoadBalancer(ctx context.Context, project, name string) error {
+	return f.remove("DeleteLoadBalancer", "loadbalancers", project, name)
+}
+
+func (f *FakeAPI) AddLoadBalancerMember(ctx context.Context, project, name string, item faxter.ServerItem) error {
+	return f.editMembers("AddLoadBalancerMember", project, name, func(members []faxter.ServerItem) ([]faxter.ServerItem, bool) {
+		return append(members, item), true
+	})
+}
+
+func (f *FakeAPI) RemoveLoadBalancerMember(ctx context.Context, project, name string, item faxter.ServerItem) error {
+	return f.editMembers("RemoveLoadBalancerMember", project, name, func(members []faxter.ServerItem) ([]faxter.ServerItem, bool) {
+		for i, m := range members {
+			if m.IP == item.IP && m.Port == item.Port {
+				return append(members[:i], members[i+1:]...), true
+			}
+		}
+		return members, false
+	})
+}
+
+func (f *FakeAPI) CreateLBProfile(ctx context.Context, req *faxter.LBProfileRequest) error {
+	obj, err := toJSONValue(req)
+	if err != nil {
+		return err
+	}
+	return f.create("CreateLBProfile", "lb_profiles", req.Project, req.Name, obj, nil)
+}
+
+func (f *FakeAPI) GetLBProfile(ctx context.Context, project, name string) (*faxter.LBProfileResponse, error) {
+	var profile faxter.LBProfileResponse
+	if err := f.get("GetLBProfile", "lb_profiles", project, name, &profile); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func (f *FakeAPI) UpdateLBProfile(ctx context.Context, project, name string, req *faxter.LBProfileRequest) error {
+	return f.update("UpdateLBProfile", "lb_profiles", project, name, req, false)
+}
+
+func (f *FakeAPI) DeleteLBProfile(ctx context.Context, project, name string) error {
+	return f.remove("DeleteLBProfile", "lb_profiles", project, name)
+}
+
+func (f *FakeAPI) CreatePlacementPolicy(ctx context.Context, req *faxter.PlacementPolicyRequest) error {
+	obj, err := toJSONValue(req)
+	if err != nil {
+		return err
+	}
+	return f.create("CreatePlacementPolicy", "placement_policies", req.Project, req.Name, obj, nil)
+}
+
+func (f *FakeAPI) GetPlacementPolicy(ctx context.Context, project, name string) (*faxter.PlacementPolicyResponse, error) {
+	var policy faxter.PlacementPolicyResponse
+	if err := f.get("GetPlacementPolicy", "placement_policies", project, name, &policy); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func (f *FakeAPI) UpdatePlacementPolicy(ctx context.Context, project, name string, req *faxter.PlacementPolicyRequest) error {
+	return f.update("UpdatePlacementPolicy", "placement_policies", project, name, req, false)
+}
+
+func (f *FakeAPI) DeletePlacementPolicy(ctx context.Context, project, name string) error {
+	return f.remove("DeletePlacementPolicy", "placement_policies", project, name)
+}
+
+func (f *FakeAPI) CreateReverseDNS(ctx context.Context, req *faxter.ReverseDNSRequest) error {
+	obj, err := toJSONValue(req)
+	if err != nil {
+		return err
+	}
+	return f.create("CreateReverseDNS", "reverse_dns", req.Project, req.FloatingIP, obj, nil)
+}
+
+func (f *FakeAPI) GetReverseDNS(ctx context.Context, project, floatingIP string) (*faxter.ReverseDNSResponse, error) {
+	var rdns faxter.ReverseDNSResponse
+	if err := f.get("GetReverseDNS", "reverse_dns", project, floatingIP, &rdns); err != nil {
+		return nil, err
+	}
+	return &rdns, nil
+}
+
+func (f *FakeAPI) UpdateReverseDNS(ctx context.Context, project, floatingIP string, req *faxter.ReverseDNSRequest) error {
+	return f.update("UpdateReverseDNS", "reverse_dns", project, floatingIP, req, false)
+}
+
+func (f *FakeAPI) DeleteReverseDNS(ctx context.Context, project, floatingIP string) error {
+	return f.remove("DeleteReverseDNS", "reverse_dns", project, floatingIP)
+}
+
+func (f *FakeAPI) CreateHostAggregate(ctx context.Context, req *faxter.HostAggregateRequest) error {
+	obj, err := toJSONValue(req)
+	if err != nil {
+		return err
+	}
+	return f.create("CreateHostAggregate", "host_aggregates", "", req.Name, obj, nil)
+}
+
+func (f *FakeAPI) GetHostAggregate(ctx context.Context, name string) (*faxter.HostAggregateResponse, error) {
+	var aggregate faxter.HostAggregateResponse
+	if err := f.get("GetHostAggregate", "host_aggregates", "", name, &aggregate); err != nil {
+		return nil, err
+	}
+	return &aggregate, nil
+}
+
+func (f *FakeAPI) UpdateHostAggregate(ctx context.Context, name string, req *faxter.HostAggregateRequest) error {
+	return f.update("UpdateHostAggregate", "host_aggregates", "", name, req, false)
+}
+
+func (f *FakeAPI) DeleteHostAggregate(ctx context.Context, name string) error {
+	return f.remove("DeleteHostAggregate", "host_aggregates", "", name)
+}
+
+func (f *FakeAPI) CreateImageMember(ctx context.Context, project, image, member string) (*faxter.ImageMemberResponse, error) {
+	obj := map[string]interface{}{"image": image, "member_project": member, "status": "pending"}
+	var imageMember faxter.ImageMemberResponse
+	if err := f.create("CreateImageMember", "image_members", project, image+"/"+member, obj, &imageMember); err != nil {
+		return nil, err
+	}
+	return &imageMember, nil
+}
+
+func (f *FakeAPI) GetImageMember(ctx context.Context, project, image, member string) (*faxter.ImageMemberResponse, error) {
+	var imageMember faxter.ImageMemberResponse
+	if err := f.get("GetImageMember", "image_members", project, image+"/"+member, &imageMember); err != nil {
+		return nil, err
+	}
+	return &imageMember, nil
+}
+
+// SetImageMemberStatus is sent on behalf of the member project, which
+// doesn't know the owner, so the sharing is found by image and member.
+func (f *FakeAPI) SetImageMemberStatus(ctx context.Context, image, member, status string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("SetImageMemberStatus"); err != nil {
+		return err
+	}
+	for k, obj := range f.store("image_members") {
+		if strings.HasSuffix(k, "/"+image+"/"+member) {
+			obj.(map[string]interface{})["status"] = status
+			return nil
+		}
+	}
+	return APIError(http.StatusNotFound, "image member not found")
+}
+
+func (f *FakeAPI) DeleteImageMember(ctx context.Context, project, image, member string) error {
+	return f.remove("DeleteImageMember", "image_members", project, image+"/"+member)
+}
+
+func (f *FakeAPI) CreateBillingAlert(ctx context.Context, req *faxter.BillingAlertRequest) error {
+	obj, err := toJSONValue(req)
+	if err != nil {
+		return err
+	}
+	return f.create("CreateBillingAlert", "billing_alerts", req.Project, req.Name, obj, nil)
+}
+
+func (f *FakeAPI) GetBillingAlert(ctx context.Context, project, name string) (*faxter.BillingAlertResponse, error) {
+	var alert faxter.BillingAlertResponse
+	if err := f.get("GetBillingAlert", "billing_alerts", project, name, &alert); err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
+
+func (f *FakeAPI) UpdateBillingAlert(ctx context.Context, project, name string, req *faxter.BillingAlertRequest) error {
+	return f.update("UpdateBillingAlert", "billing_alerts", project, name, req, false)
+}
+
+func (f *FakeAPI) DeleteBillingAlert(ctx context.Context, project, name string) error {
+	return f.remove("DeleteBillingAlert", "billing_alerts", project, name)
+}
+
+// CreateQuotaRequest files a request that stays "pending"; use SetStatus on
+// "quota_requests" to approve or reject it.
+func (f *FakeAPI) CreateQuotaRequest(ctx context.Context, req *faxter.QuotaRequestRequest) (*faxter.QuotaRequestResponse, error) {
+	obj, err := toJSONValue(req)
+	if err != nil {
+		return nil, err
+	}
+	id := f.newID("quota")
+	obj["id"] = id
+	obj["status"] = "pending"
+	var quotaReq faxter.QuotaRequestResponse
+	if err := f.create("CreateQuotaRequest", "quota_requests", req.Project, id, obj, &quotaReq); err != nil {
+		return nil, err
+	}
+	return &quotaReq, nil
+}
+
+func (f *FakeAPI) GetQuotaRequest(ctx context.Context, project, id string) (*faxter.QuotaRequestResponse, error) {
+	var quotaReq faxter.QuotaRequestResponse
+	if err := f.get("GetQuotaRequest", "quota_requests", project, id, &quotaReq); err != nil {
+		return nil, err
+	}
+	return &quotaReq, nil
+}
+
+func (f *FakeAPI) WithdrawQuotaRequest(ctx context.Context, project, id string) error {
+	return f.remove("WithdrawQuotaRequest", "quota_requests", project, id)
+}
+
+func (f *FakeAPI) PutBucketPolicy(ctx context.Context, project, bucket string, req *faxter.BucketPolicyRequest) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("PutBucketPolicy"); err != nil {
+		return err
+	}
+	obj, err := toJSONValue(&faxter.BucketPolicyResponse{Bucket: bucket, Policy: req.Policy})
+	if err != nil {
+		return err
+	}
+	f.store("bucket_policies")[key("bucket_policies", project, bucket)] = obj
+	return nil
+}
+
+func (f *FakeAPI) GetBucketPolicy(ctx context.Context, project, bucket string) (*faxter.BucketPolicyResponse, error) {
+	var policy faxter.BucketPolicyResponse
+	if err := f.get("GetBucketPolicy", "bucket_policies", project, bucket, &policy); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func (f *FakeAPI) DeleteBucketPolicy(ctx context.Context, project, bucket string) error {
+	return f.remove("DeleteBucketPolicy", "bucket_policies", project, bucket)
+}
+
+func (f *FakeAPI) Lock(ctx context.Context, collection, project, name, reason string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("Lock"); err != nil {
+		return err
+	}
+	if _, ok := f.store(collection)[key(collection, project, name)]; !ok {
+		return APIError(http.StatusNotFound, "not found")
+	}
+	f.store("locks")[key("locks", project, collection+"/"+name)] = map[string]interface{}{"locked": true, "reason": reason}
+	return nil
+}
+
+// GetLock reports an object without a lock as unlocked.
+func (f *FakeAPI) GetLock(ctx context.Context, collection, project, name string) (*faxter.LockResponse, error) {
+	var lock faxter.LockResponse
+	err := f.get("GetLock", "locks", project, collection+"/"+name, &lock)
+	if err != nil && !faxter.IsNotFound(err) {
+		return nil, err
+	}
+	return &lock, nil
+}
+
+func (f *FakeAPI) Unlock(ctx context.Context, collection, project, name string) error {
+	return f.remove("Unlock", "locks", project, collection+"/"+name)
+}
+
+// record notes a call to method and returns the error configured for it.
+// f.mu must be held.
+func (f *FakeAPI) record(method string) error {
+	f.calls = append(f.calls, method)
+	return f.Errors[method]
+}
+
+func (f *FakeAPI) store(collection string) map[string]interface{} {
+	objs, ok := f.objects[collection]
+	if !ok {
+		objs = map[string]interface{}{}
+		f.objects[collection] = objs
+	}
+	return objs
+}
+
+func (f *FakeAPI) newID(prefix string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextID++
+	return fmt.Sprintf("%s-%d", prefix, f.nextID)
+}
+
+func (f *FakeAPI) status(collection string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if status := f.Statuses[collection]; status != "" {
+		return status
+	}
+	if collection == "servers" {
+		return "online"
+	}
+	return "active"
+}
+
+// resource returns the object the API keeps for a resource created with
+// req: its name, status and an ID, with the request as its properties.
+func (f *FakeAPI) resource(collection, name string, req interface{}) (map[string]interface{}, error) {
+	properties, err := toJSONValue(req)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"id":         f.newID(strings.TrimSuffix(collection, "s")),
+		"name":       name,
+		"status":     f.status(collection),
+		"properties": properties,
+	}, nil
+}
+
+// flat returns the object the API keeps for req when it reports the fields
+// at the top level, along with a status.
+func (f *FakeAPI) flat(collection string, req interface{}) (map[string]interface{}, error) {
+	obj, err := toJSONValue(req)
+	if err != nil {
+		return nil, err
+	}
+	obj["status"] = f.status(collection)
+	return obj, nil
+}
+
+func (f *FakeAPI) rule(req *faxter.SecurityGroupRuleRequest) (map[string]interface{}, error) {
+	rule, err := toJSONValue(req)
+	if err != nil {
+		return nil, err
+	}
+	rule["id"] = f.newID("rule")
+	return rule, nil
+}
+
+func (f *FakeAPI) createResource(method, collection, project, name string, req interface{}) (*faxter.ResourceResponse, error) {
+	obj, err := f.resource(collection, name, req)
+	if err != nil {
+		return nil, err
+	}
+	var resp faxter.ResourceResponse
+	if err := f.create(method, collection, project, name, obj, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (f *FakeAPI) getResource(method, collection, project, name string) (*faxter.ResourceResponse, error) {
+	var resp faxter.ResourceResponse
+	if err := f.get(method, collection, project, name, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (f *FakeAPI) create(method, collection, project, name string, obj map[string]interface{}, out interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(method); err != nil {
+		return err
+	}
+	k := key(collection, project, name)
+	if _, exists := f.store(collection)[k]; exists {
+		return APIError(http.StatusConflict, fmt.Sprintf("%s already exists", name))
+	}
+	f.store(collection)[k] = obj
+	if out == nil {
+		return nil
+	}
+	return fromJSONValue(obj, out)
+}
+
+func (f *FakeAPI) get(method, collection, project, name string, out interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(method); err != nil {
+		return err
+	}
+	obj, ok := f.store(collection)[key(collection, project, name)]
+	if !ok {
+		return APIError(http.StatusNotFound, fmt.Sprintf("%s not found", name))
+	}
+	if out == nil {
+		return nil
+	}
+	return fromJSONValue(obj, out)
+}
+
+// list decodes the objects of collection whose keys start with prefix, in
+// key order, into out.
+func (f *FakeAPI) list(method, collection, prefix string, out interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(method); err != nil {
+		return err
+	}
+	var keys []string
+	for k := range f.store(collection) {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	objs := make([]interface{}, 0, len(keys))
+	for _, k := range keys {
+		objs = append(objs, f.store(collection)[k])
+	}
+	return fromJSONValue(objs, out)
+}
+
+// update merges the fields set in req into a stored object, or into its
+// properties when nested is set, moving it when req renames it.
+func (f *FakeAPI) update(method, collection, project, name string, req interface{}, nested bool) error {
+	patch, err := toJSONValue(req)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(method); err != nil {
+		return err
+	}
+	k := key(collection, project, name)
+	obj, ok := f.store(collection)[k].(map[string]interface{})
+	if !ok {
+		return APIError(http.StatusNotFound, fmt.Sprintf("%s not found", name))
+	}
+
+	target := obj
+	if properties, ok := obj["properties"].(map[string]interface{}); nested && ok {
+		target = properties
+	}
+	for field, value := range patch {
+		if value != nil {
+			target[field] = value
+		}
+	}
+
+	if newName, _ := patch["name"].(string); newName != "" && newName != name {
+		obj["name"] = newName
+		delete(f.store(collection), k)
+		f.store(collection)[key(collection, project, newName)] = obj
+	}
+	return nil
+}
+
+func (f *FakeAPI) remove(method, collection, project, name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(method); err != nil {
+		return err
+	}
+	k := key(collection, project, name)
+	if _, ok := f.store(collection)[k]; !ok {
+		return APIError(http.StatusNotFound, fmt.Sprintf("%s not found", name))
+	}
+	delete(f.store(collection), k)
+	return nil
+}
+
+// replaceRules replaces the stored rules of a security group, which may have
+// been renamed from oldName to newName.
+func (f *FakeAPI) replaceRules(project, oldName, newName string, rules []faxter.SecurityGroupRuleRequest) error {
+	stored := make([]map[string]interface{}, 0, len(rules))
+	for i := range rules {
+		rule, err := f.rule(&rules[i])
+		if err != nil {
+			return err
+		}
+		stored = append(stored, rule)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	objs := f.store("security_group_rules")
+	prefix := key("security_group_rules", project, oldName+"/")
+	for k := range objs {
+		if strings.HasPrefix(k, prefix) {
+			delete(objs, k)
+		}
+	}
+	for _, rule := range stored {
+		objs[key("security_group_rules", project, newName+"/"+rule["id"].(string))] = rule
+	}
+	return nil
+}
+
+// editServerVolumes applies edit to the volumes of a server and sets the
+// volume's status. edit reports false for a volume that is already attached,
+// or not attached, as the API would answer 409 for.
+func (f *FakeAPI) editServerVolumes(method, project, server, volume, status string, edit func([]string) ([]string, bool)) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(method); err != nil {
+		return err
+	}
+	obj, ok := f.store("servers")[key("servers", project, server)].(map[string]interface{})
+	if !ok {
+		return APIError(http.StatusNotFound, fmt.Sprintf("%s not found", server))
+	}
+	volumeObj, ok := f.store("volumes")[key("volumes", project, volume)].(map[string]interface{})
+	if !ok {
+		return APIError(http.StatusNotFound, fmt.Sprintf("%s not found", volume))
+	}
+	properties, _ := obj["properties"].(map[string]interface{})
+	if properties == nil {
+		properties = map[string]interface{}{}
+		obj["properties"] = properties
+	}
+
+	var volumes []string
+	if err := fromJSONValue(properties["volumes"], &volumes); err != nil {
+		return err
+	}
+	volumes, ok = edit(volumes)
+	if !ok {
+		return APIError(http.StatusConflict, fmt.Sprintf("volume %s is not in a state to be changed", volume))
+	}
+	value, err := toJSONValue(map[string]interface{}{"volumes": volumes})
+	if err != nil {
+		return err
+	}
+	properties["volumes"] = value["volumes"]
+	volumeObj["status"] = status
+	return nil
+}
+
+func (f *FakeAPI) editMembers(method, project, name string, edit func([]faxter.ServerItem) ([]faxter.ServerItem, bool)) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(method); err != nil {
+		return err
+	}
+	obj, ok := f.store("loadbalancers")[key("loadbalancers", project, name)].(map[string]interface{})
+	if !ok {
+		return APIError(http.StatusNotFound, fmt.Sprintf("%s not found", name))
+	}
+	properties, _ := obj["properties"].(map[string]interface{})
+	if properties == nil {
+		properties = map[string]interface{}{}
+		obj["properties"] = properties
+	}
+
+	var members []faxter.ServerItem
+	if err := fromJSONValue(properties["servers"], &members); err != nil {
+		return err
+	}
+	members, ok = edit(members)
+	if !ok {
+		return APIError(http.StatusNotFound, "member not found")
+	}
+	servers, err := toJSONValue(map[string]interface{}{"servers": members})
+	if err != nil {
+		return err
+	}
+	properties["servers"] = servers["servers"]
+	return nil
+}
+
+// toJSONValue returns v as the generic value its JSON decodes to; objects
+// become maps.
+func toJSONValue(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var value map[string]interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func fromJSONValue(value, out interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
@@ -0,0 +1,104 @@
+package faxtertest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ProviderConfig renders a provider block authenticating with token.
+func ProviderConfig(token string) string {
+	return fmt.Sprintf(`
+provider "faxter" {
+  token = %q
+}
+`, token)
+}
+
+// ProviderConfig renders a provider block pointed at the mock server.
+func (s *Server) ProviderConfig() string {
+	return fmt.Sprintf(`
+provider "faxter" {
+  base_url = %q
+  token    = %q
+}
+`, s.URL, s.Token)
+}
+
+// ProjectConfig renders a faxter_project resource.
+func ProjectConfig(label, name string) string {
+	return fmt.Sprintf(`
+resource "faxter_project" %q {
+  name = %q
+}
+`, label, name)
+}
+
+// SSHKeyConfig renders a faxter_ssh_key resource.
+func SSHKeyConfig(label, project, name, publicKey string) string {
+	return fmt.Sprintf(`
+resource "faxter_ssh_key" %q {
+  project    = %q
+  name       = %q
+  public_key = %q
+}
+`, label, project, name, publicKey)
+}
+
+// ServerConfig renders a faxter_server resource using the provider's default
+// flavor, image and networks.
+func ServerConfig(label, project, name, keyName string) string {
+	return fmt.Sprintf(`
+resource "faxter_server" %q {
+  project  = %q
+  name     = %q
+  key_name = %q
+}
+`, label, project, name, keyName)
+}
+
+// NetworkConfig renders a faxter_network resource with one subnet per entry
+// in subnets, which maps subnet names to CIDRs.
+func NetworkConfig(label, project, name string, subnets map[string]string) string {
+	var b strings.Builder
+	for _, subnetName := range sortedKeys(subnets) {
+		fmt.Fprintf(&b, `
+  subnets {
+    name = %q
+    cidr = %q
+  }
+`, subnetName, subnets[subnetName])
+	}
+
+	return fmt.Sprintf(`
+resource "faxter_network" %q {
+  project = %q
+  name    = %q
+%s}
+`, label, project, name, b.String())
+}
+
+// VolumeConfig renders a faxter_volume resource of the given size in GB.
+func VolumeConfig(label, project, name string, storage int) string {
+	return fmt.Sprintf(`
+resource "faxter_volume" %q {
+  project = %q
+  name    = %q
+  storage = %d
+}
+`, label, project, name, storage)
+}
+
+// Compose joins configuration fragments into a single configuration.
+func Compose(fragments ...string) string {
+	return strings.Join(fragments, "\n")
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
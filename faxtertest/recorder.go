@@ -0,0 +1,254 @@
+package faxtertest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// Mode selects whether a Recorder talks to the real API or to its cassette.
+type Mode string
+
+const (
+	// ModeRecord forwards requests to the real API and saves the exchanges.
+	ModeRecord Mode = "record"
+	// ModeReplay answers requests from a cassette saved earlier.
+	ModeReplay Mode = "replay"
+)
+
+// Interaction is one request and the API's response to it, as saved in a
+// cassette.
+type Interaction struct {
+	Method string `json:"method"`
+	// Path includes the query string, e.g. "/servers/web?project_name=acme".
+	Path        string `json:"path"`
+	RequestBody string `json:"request_body,omitempty"`
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        string `json:"body"`
+}
+
+// Recorder sits between the provider and the Faxter API so that an
+// acceptance test can be recorded once against a real account and replayed
+// deterministically, e.g. in CI, without credentials. Point the provider at
+// it with ProviderConfig.
+//
+// The mode is taken from the environment: with FAXTER_RECORD set to a
+// non-empty value requests go to FAXTER_BASE_URL (by default the hosted API)
+// authenticated with FAXTER_TOKEN, and the exchanges are written to the
+// cassette by Stop; otherwise they are answered from the cassette.
+//
+// A replayed request gets the first recorded response to the same method
+// and path that hasn't been replayed yet, so a status polled while a server
+// builds comes back in the order it was seen. Once those run out, the last
+// one is repeated.
+type Recorder struct {
+	*httptest.Server
+
+	// Mode is the mode the recorder was started in.
+	Mode Mode
+
+	// Token is the token the provider should authenticate with: the real one
+	// when recording and a placeholder when replaying.
+	Token string
+
+	// Redact, when set, is called on every interaction before it is saved,
+	// to scrub secrets such as passwords or issued tokens from the cassette.
+	// Authorization headers are never saved.
+	Redact func(*Interaction)
+
+	cassette string
+	upstream *url.URL
+
+	mu           sync.Mutex
+	interactions []Interaction
+	replayed     []bool
+	unmatched    []string
+}
+
+// NewRecorder starts a recorder for the cassette at path, a JSON file that
+// is conventionally kept under testdata/. Callers must Stop it when done.
+func NewRecorder(path string) (*Recorder, error) {
+	r := &Recorder{cassette: path}
+
+	if os.Getenv("FAXTER_RECORD") != "" {
+		baseURL := os.Getenv("FAXTER_BASE_URL")
+		if baseURL == "" {
+			baseURL = faxter.DefaultBaseURL
+		}
+		upstream, err := url.Parse(strings.TrimRight(baseURL, "/"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid FAXTER_BASE_URL: %w", err)
+		}
+		r.Mode = ModeRecord
+		r.Token = os.Getenv("FAXTER_TOKEN")
+		r.upstream = upstream
+	} else {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("cassette %s not found; record it by running the test with FAXTER_RECORD=1 and FAXTER_TOKEN set", path)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &r.interactions); err != nil {
+			return nil, fmt.Errorf("invalid cassette %s: %w", path, err)
+		}
+		r.Mode = ModeReplay
+		r.Token = "replay"
+		r.replayed = make([]bool, len(r.interactions))
+	}
+
+	r.Server = httptest.NewServer(http.HandlerFunc(r.handle))
+	return r, nil
+}
+
+// Stop shuts the recorder down. When recording, it then saves the cassette;
+// when replaying, it reports any request that had no recorded response.
+func (r *Recorder) Stop() error {
+	r.Close()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.Mode == ModeReplay {
+		if len(r.unmatched) > 0 {
+			return fmt.Errorf("cassette %s has no response for %s; record it again", r.cassette, strings.Join(r.unmatched, ", "))
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(r.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.cassette, append(data, '\n'), 0o644)
+}
+
+// ProviderConfig renders a provider block pointed at the recorder. Status
+// polls are made every second, which is as often as the API is asked when
+// recording and costs little time when replaying.
+func (r *Recorder) ProviderConfig() string {
+	return fmt.Sprintf(`
+provider "faxter" {
+  base_url      = %q
+  token         = %q
+  poll_schedule = ["1s"]
+}
+`, r.URL, r.Token)
+}
+
+// CheckExists is CheckExists bound to the recorder.
+func (r *Recorder) CheckExists(addr string) func(*terraform.State) error {
+	return CheckExists(r.URL, r.Token, addr)
+}
+
+// CheckDestroy is CheckDestroy bound to the recorder.
+func (r *Recorder) CheckDestroy(resourceType string) func(*terraform.State) error {
+	return CheckDestroy(r.URL, r.Token, resourceType)
+}
+
+func (r *Recorder) handle(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		writeDetail(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if r.Mode == ModeReplay {
+		r.replay(w, req)
+		return
+	}
+	r.record(w, req, body)
+}
+
+func (r *Recorder) replay(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	path := req.URL.RequestURI()
+	match := -1
+	for i, in := range r.interactions {
+		if in.Method != req.Method || in.Path != path {
+			continue
+		}
+		match = i
+		if !r.replayed[i] {
+			break
+		}
+	}
+	if match < 0 {
+		r.unmatched = append(r.unmatched, req.Method+" "+path)
+		writeDetail(w, http.StatusNotImplemented, fmt.Sprintf("no recorded response for %s %s", req.Method, path))
+		return
+	}
+	r.replayed[match] = true
+
+	in := r.interactions[match]
+	if in.ContentType != "" {
+		w.Header().Set("Content-Type", in.ContentType)
+	}
+	w.WriteHeader(in.Status)
+	io.WriteString(w, in.Body)
+}
+
+func (r *Recorder) record(w http.ResponseWriter, req *http.Request, body []byte) {
+	target := *r.upstream
+	target.Path = strings.TrimRight(target.Path, "/") + req.URL.Path
+	target.RawQuery = req.URL.RawQuery
+
+	out, err := http.NewRequestWithContext(req.Context(), req.Method, target.String(), bytes.NewReader(body))
+	if err != nil {
+		writeDetail(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	out.Header = req.Header.Clone()
+	// Leave compression to the transport, which then hands back the body
+	// decompressed, so that the cassette stays readable.
+	out.Header.Del("Accept-Encoding")
+
+	resp, err := http.DefaultClient.Do(out)
+	if err != nil {
+		writeDetail(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		writeDetail(w, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	in := Interaction{
+		Method:      req.Method,
+		Path:        req.URL.RequestURI(),
+		RequestBody: string(body),
+		Status:      resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+		Body:        string(respBody),
+	}
+	if r.Redact != nil {
+		r.Redact(&in)
+	}
+	r.mu.Lock()
+	r.interactions = append(r.interactions, in)
+	r.mu.Unlock()
+
+	for k, v := range resp.Header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(resp.StatusCode)
+	w.Write(respBody)
+}
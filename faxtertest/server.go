@@ -0,0 +1,302 @@
+// Package faxtertest provides helpers for writing acceptance tests against the
+// Faxter provider: an in-memory mock of the Faxter API, CheckExists and
+// CheckDestroy functions for use in test steps, and fixture builders that
+// render Terraform configuration for the provider's resources.
+//
+// The mock and the checks support the resource types listed in collections:
+// those the API addresses as /<collection>/<name>. Resources kept under
+// another object or at a path of their own (faxter_security_group_rule,
+// faxter_billing_alert, faxter_object_storage_bucket_policy,
+// faxter_image_member, faxter_script and faxter_resource_lock) and
+// faxter_quota_request, whose ID the API assigns, are not supported.
+package faxtertest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+// collections maps each Terraform resource type to its API collection.
+var collections = map[string]string{
+	"faxter_project":          "projects",
+	"faxter_server":           "servers",
+	"faxter_ssh_key":          "ssh_keys",
+	"faxter_network":          "networks",
+	"faxter_router":           "routers",
+	"faxter_volume":           "volumes",
+	"faxter_security_group":   "security_groups",
+	"faxter_loadbalancer":     "loadbalancers",
+	"faxter_gateway_service":  "gateway_services",
+	"faxter_host_aggregate":   "host_aggregates",
+	"faxter_lb_profile":       "lb_profiles",
+	"faxter_placement_policy": "placement_policies",
+	"faxter_qos_policy":       "qos_policies",
+	"faxter_reverse_dns":      "reverse_dns",
+}
+
+// unscoped lists the collections whose objects are not addressed by project.
+var unscoped = map[string]bool{
+	"projects":        true,
+	"ssh_keys":        true,
+	"host_aggregates": true,
+}
+
+// plain lists the collections whose objects the API returns as they are
+// rather than wrapped in a resourceResponse.
+var plain = map[string]bool{
+	"gateway_services":   true,
+	"host_aggregates":    true,
+	"lb_profiles":        true,
+	"placement_policies": true,
+	"qos_policies":       true,
+	"reverse_dns":        true,
+}
+
+// nameFields lists the collections whose objects are named by a field other
+// than "name".
+var nameFields = map[string]string{
+	"reverse_dns": "floating_ip",
+}
+
+// nameField returns the field that names the objects of collection.
+func nameField(collection string) string {
+	if field, ok := nameFields[collection]; ok {
+		return field
+	}
+	return "name"
+}
+
+// resourceResponse mirrors the shape the Faxter API returns for a resource.
+type resourceResponse struct {
+	Name       string                 `json:"name"`
+	Status     string                 `json:"status"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// Server is an in-memory stand-in for the Faxter API. It accepts the same
+// create, read, update and delete calls the provider makes and keeps the
+// submitted objects so tests can assert on them.
+type Server struct {
+	*httptest.Server
+
+	// Token, if set, is the bearer token every request must carry.
+	Token string
+
+	mu        sync.Mutex
+	resources map[string]map[string]map[string]interface{}
+}
+
+// NewServer starts a mock Faxter API. Callers must Close it when done.
+func NewServer(token string) *Server {
+	s := &Server{
+		Token:     token,
+		resources: make(map[string]map[string]map[string]interface{}),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// Seed stores an object as if it had been created through the API, so a test
+// can start from existing infrastructure. project is ignored for resource
+// types that are not project-scoped.
+func (s *Server) Seed(resourceType, project, name string, properties map[string]interface{}) error {
+	collection, ok := collections[resourceType]
+	if !ok {
+		return fmt.Errorf("resource type %q is not supported by faxtertest", resourceType)
+	}
+
+	obj := map[string]interface{}{}
+	for k, v := range properties {
+		obj[k] = v
+	}
+	obj[nameField(collection)] = name
+	if !unscoped[collection] {
+		obj["project"] = project
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.store(collection)[key(collection, project, name)] = obj
+	return nil
+}
+
+// Exists reports whether the mock currently holds the named object.
+func (s *Server) Exists(resourceType, project, name string) bool {
+	collection, ok := collections[resourceType]
+	if !ok {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, found := s.store(collection)[key(collection, project, name)]
+	return found
+}
+
+func (s *Server) store(collection string) map[string]map[string]interface{} {
+	objs, ok := s.resources[collection]
+	if !ok {
+		objs = make(map[string]map[string]interface{})
+		s.resources[collection] = objs
+	}
+	return objs
+}
+
+func key(collection, project, name string) string {
+	if unscoped[collection] {
+		return name
+	}
+	if project == "" {
+		project = "default"
+	}
+	return project + "/" + name
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	if s.Token != "" && r.Header.Get("Authorization") != "Bearer "+s.Token {
+		writeDetail(w, http.StatusUnauthorized, "invalid token")
+		return
+	}
+
+	parts := strings.SplitN(strings.Trim(r.URL.Path, "/"), "/", 2)
+	collection := parts[0]
+	if !knownCollection(collection) {
+		writeDetail(w, http.StatusNotFound, "not found")
+		return
+	}
+	name := ""
+	if len(parts) == 2 {
+		name = parts[1]
+	}
+	project := r.URL.Query().Get("project_name")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	objs := s.store(collection)
+
+	switch {
+	case r.Method == http.MethodPost && name == "":
+		var obj map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&obj); err != nil {
+			writeDetail(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		objName, _ := obj[nameField(collection)].(string)
+		objProject, _ := obj["project"].(string)
+		k := key(collection, objProject, objName)
+		if _, exists := objs[k]; exists {
+			writeDetail(w, http.StatusConflict, fmt.Sprintf("%s already exists", objName))
+			return
+		}
+		objs[k] = obj
+
+		resp := toResponse(collection, obj)
+		if collection == "servers" {
+			writeJSON(w, http.StatusOK, []interface{}{resp})
+			return
+		}
+		writeJSON(w, http.StatusOK, resp)
+
+	case r.Method == http.MethodGet && name == "":
+		list := []interface{}{}
+		for _, obj := range objs {
+			if objProject, _ := obj["project"].(string); unscoped[collection] || objProject == project {
+				list = append(list, toResponse(collection, obj))
+			}
+		}
+		writeJSON(w, http.StatusOK, list)
+
+	case r.Method == http.MethodGet && name != "":
+		obj, ok := objs[key(collection, project, name)]
+		if !ok {
+			writeDetail(w, http.StatusNotFound, fmt.Sprintf("%s not found", name))
+			return
+		}
+		writeJSON(w, http.StatusOK, toResponse(collection, obj))
+
+	case r.Method == http.MethodPut && name != "":
+		k := key(collection, project, name)
+		obj, ok := objs[k]
+		if !ok {
+			writeDetail(w, http.StatusNotFound, fmt.Sprintf("%s not found", name))
+			return
+		}
+		var update map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			writeDetail(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		for field, v := range update {
+			obj[field] = v
+		}
+		if newName, _ := obj[nameField(collection)].(string); newName != "" && newName != name {
+			delete(objs, k)
+			objs[key(collection, project, newName)] = obj
+		}
+		writeJSON(w, http.StatusOK, toResponse(collection, obj))
+
+	case r.Method == http.MethodDelete && name != "":
+		k := key(collection, project, name)
+		if _, ok := objs[k]; !ok {
+			writeDetail(w, http.StatusNotFound, fmt.Sprintf("%s not found", name))
+			return
+		}
+		delete(objs, k)
+		writeJSON(w, http.StatusOK, map[string]string{"detail": "deleted"})
+
+	default:
+		writeDetail(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func knownCollection(collection string) bool {
+	for _, c := range collections {
+		if c == collection {
+			return true
+		}
+	}
+	return false
+}
+
+func toResponse(collection string, obj map[string]interface{}) interface{} {
+	if plain[collection] {
+		resp := map[string]interface{}{}
+		for k, v := range obj {
+			if k != "project" {
+				resp[k] = v
+			}
+		}
+		return resp
+	}
+
+	props := map[string]interface{}{}
+	for k, v := range obj {
+		props[k] = v
+	}
+	if collection == "servers" {
+		if _, ok := props["ip_addresses"]; !ok {
+			props["ip_addresses"] = []string{"10.0.0.10"}
+		}
+	}
+
+	name, _ := obj["name"].(string)
+	return resourceResponse{
+		Name:       name,
+		Status:     "online",
+		Properties: props,
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeDetail(w http.ResponseWriter, status int, detail string) {
+	writeJSON(w, status, map[string]string{"detail": detail})
+}
@@ -0,0 +1,105 @@
+package faxtertest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// state returns a state holding one resource of resourceType, as the
+// provider would record it.
+func state(addr, resourceType, id, project string) *terraform.State {
+	s := terraform.NewState()
+	s.RootModule().Resources[addr] = &terraform.ResourceState{
+		Type: resourceType,
+		Primary: &terraform.InstanceState{
+			ID:         id,
+			Attributes: map[string]string{"id": id, "project": project},
+		},
+	}
+	return s
+}
+
+func TestServerChecks(t *testing.T) {
+	cases := []struct {
+		resourceType, project, name string
+	}{
+		{"faxter_volume", "acme", "data"},
+		{"faxter_ssh_key", "", "deploy"},
+		{"faxter_placement_policy", "acme", "spread-web"},
+		{"faxter_host_aggregate", "", "gpu"},
+		{"faxter_reverse_dns", "acme", "203.0.113.7"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.resourceType, func(t *testing.T) {
+			s := NewServer("token")
+			defer s.Close()
+
+			addr := tc.resourceType + ".test"
+			st := state(addr, tc.resourceType, tc.name, tc.project)
+			if err := s.CheckExists(addr)(st); err == nil {
+				t.Fatal("CheckExists passed before the object was created")
+			}
+			if err := s.CheckDestroy(tc.resourceType)(st); err != nil {
+				t.Fatalf("CheckDestroy: %s", err)
+			}
+
+			if err := s.Seed(tc.resourceType, tc.project, tc.name, nil); err != nil {
+				t.Fatalf("Seed: %s", err)
+			}
+			if err := s.CheckExists(addr)(st); err != nil {
+				t.Fatalf("CheckExists: %s", err)
+			}
+			if err := s.CheckDestroy(tc.resourceType)(st); err == nil {
+				t.Fatal("CheckDestroy passed while the object exists")
+			}
+		})
+	}
+}
+
+func TestServerUnsupportedType(t *testing.T) {
+	s := NewServer("")
+	defer s.Close()
+
+	st := state("faxter_quota_request.test", "faxter_quota_request", "qr-1", "acme")
+	if err := s.CheckExists("faxter_quota_request.test")(st); err == nil {
+		t.Fatal("CheckExists passed for a resource type the mock doesn't support")
+	}
+	if err := s.Seed("faxter_quota_request", "acme", "qr-1", nil); err == nil {
+		t.Fatal("Seed passed for a resource type the mock doesn't support")
+	}
+}
+
+// TestServerClient runs the client's calls for a resource the API returns
+// unwrapped against the mock.
+func TestServerClient(t *testing.T) {
+	s := NewServer("token")
+	defer s.Close()
+	c := faxter.NewClient(s.URL, "token")
+	ctx := context.Background()
+
+	err := c.CreatePlacementPolicy(ctx, &faxter.PlacementPolicyRequest{
+		Project:  "acme",
+		Name:     "spread-web",
+		Strategy: "spread",
+	})
+	if err != nil {
+		t.Fatalf("CreatePlacementPolicy: %s", err)
+	}
+	policy, err := c.GetPlacementPolicy(ctx, "acme", "spread-web")
+	if err != nil {
+		t.Fatalf("GetPlacementPolicy: %s", err)
+	}
+	if policy.Name != "spread-web" || policy.Strategy != "spread" {
+		t.Errorf("got policy %+v", policy)
+	}
+
+	if err := c.DeletePlacementPolicy(ctx, "acme", "spread-web"); err != nil {
+		t.Fatalf("DeletePlacementPolicy: %s", err)
+	}
+	if s.Exists("faxter_placement_policy", "acme", "spread-web") {
+		t.Error("policy still exists after DeletePlacementPolicy")
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// isFloatingIPExhausted reports whether err is the API's conflict for a
+// project whose floating IP pool has no free addresses.
+func isFloatingIPExhausted(err error) bool {
+	var apiErr *faxter.Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict && strings.Contains(strings.ToLower(apiErr.Body), "floating")
+}
+
+// doFloatingIPRequest runs send, a request that may allocate a floating IP.
+// While the project's pool is exhausted the request is resent, following the
+// poll schedule, for up to the provider's floating_ip_wait; after that the
+// condition is reported with a diagnostic naming the project. Any other error
+// fails action, e.g. "create server".
+func doFloatingIPRequest(ctx context.Context, c *Client, project, action string, send func() error) diag.Diagnostics {
+	start := time.Now()
+	deadline := start.Add(c.floatingIPWait)
+
+	for attempt := 0; ; attempt++ {
+		err := send()
+		if err == nil {
+			return nil
+		}
+		if !isFloatingIPExhausted(err) {
+			return apiErrorDiag("Failed to "+action, err)
+		}
+
+		delay := pollDelay(c.pollSchedule, attempt)
+		if time.Now().Add(delay).After(deadline) {
+			detail := "Release unused floating IPs in the project or request a larger quota."
+			if c.floatingIPWait > 0 {
+				detail = fmt.Sprintf("No address was released within %s. %s", time.Since(start).Round(time.Second), detail)
+			} else {
+				detail += " To wait for addresses to be released instead of failing, set floating_ip_wait on the provider."
+			}
+			return diag.Diagnostics{{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Floating IP pool exhausted in project '%s'", project),
+				Detail:   fmt.Sprintf("%s\n\nAPI response: %s", detail, err),
+			}}
+		}
+
+		tflog.Info(ctx, "Floating IP pool exhausted, waiting for addresses to be released", map[string]interface{}{
+			"project": project,
+			"attempt": attempt + 1,
+			"wait":    delay.String(),
+		})
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return diag.FromErr(ctx.Err())
+		}
+	}
+}
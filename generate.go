@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/signal"
+	"regexp"
+	"strings"
+
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// generateTargets are the resource types the generate command discovers, in
+// dependency order, with the API collection each one is listed from.
+var generateTargets = []struct {
+	resourceType string
+	collection   string
+}{
+	{"faxter_ssh_key", "ssh_keys"},
+	{"faxter_qos_policy", "qos_policies"},
+	{"faxter_network", "networks"},
+	{"faxter_router", "routers"},
+	{"faxter_gateway_service", "gateway_services"},
+	{"faxter_security_group", "security_groups"},
+	{"faxter_volume", "volumes"},
+	{"faxter_server", "servers"},
+	{"faxter_loadbalancer", "loadbalancers"},
+}
+
+// runGenerate implements the "generate" command:
+//
+//	terraform-provider-faxter generate [-project NAME] [-out FILE]
+//
+// It lists the resources of a project and writes an import block plus a
+// resource skeleton for each, so an existing tenant can be brought under
+// Terraform with "terraform plan -generate-config-out" or by filling in the
+// skeletons. Credentials and endpoint are taken from the same FAXTER_*
+// environment variables the provider block falls back to.
+func runGenerate(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	project := flags.String("project", "", "project to list (defaults to FAXTER_PROJECT, then \"default\")")
+	out := flags.String("out", "", "file to write instead of stdout")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	// Stop listing when interrupted rather than finishing every request.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	p := Provider()
+	if diags := p.Configure(ctx, terraform.NewResourceConfigRaw(map[string]interface{}{})); diags.HasError() {
+		for _, d := range diags {
+			fmt.Fprintf(stderr, "Error: %s\n", d.Summary)
+		}
+		return 1
+	}
+	c := p.Meta().(*Client)
+	if *project == "" {
+		*project = c.defaultProject
+	}
+
+	w := stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %s\n", err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+
+	fmt.Fprintf(w, "# Generated by terraform-provider-faxter generate for project %q.\n", *project)
+	for _, target := range generateTargets {
+		names, err := listNames(ctx, c, target.collection, *project)
+		if err != nil {
+			// Collections can be unavailable on some deployments; keep going.
+			fmt.Fprintf(stderr, "Warning: skipping %s: %s\n", target.resourceType, err)
+			continue
+		}
+		for _, name := range names {
+			if target.resourceType == "faxter_security_group" {
+				rules, err := c.api.ListSecurityGroupRules(ctx, *project, name)
+				if err != nil {
+					fmt.Fprintf(stderr, "Warning: skipping security group %s: failed to list its rules: %s\n", name, err)
+					continue
+				}
+				writeSecurityGroupImportBlocks(w, *project, name, rules)
+				continue
+			}
+			writeImportBlock(w, target.resourceType, *project, name)
+		}
+	}
+
+	return 0
+}
+
+// listNames returns the names of the objects in a collection.
+func listNames(ctx context.Context, c *Client, collection, project string) ([]string, error) {
+	path := fmt.Sprintf("/%s/?project_name=%s", collection, url.QueryEscape(project))
+	items, err := faxter.List[struct {
+		Name string `json:"name"`
+	}](ctx, c.rest, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", collection, err)
+	}
+
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, item.Name)
+	}
+	return names, nil
+}
+
+func writeImportBlock(w io.Writer, resourceType, project, name string) {
+	label := terraformLabel(name)
+	fmt.Fprintf(w, `
+import {
+  to = %s.%s
+  id = %q
+}
+
+resource %q %q {
+  project = %q
+  name    = %q
+}
+`, resourceType, label, project+"/"+name, resourceType, label, project, name)
+}
+
+// writeSecurityGroupImportBlocks writes a security group with manage_rules
+// off and one faxter_security_group_rule per rule. Importing a group
+// doesn't bring its rules along, so they are imported as resources of their
+// own, with their arguments filled in: rules can't be changed in place, so a
+// skeleton left with the defaults would replace them.
+func writeSecurityGroupImportBlocks(w io.Writer, project, name string, rules []faxter.SecurityGroupRuleResponse) {
+	label := terraformLabel(name)
+	fmt.Fprintf(w, `
+import {
+  to = faxter_security_group.%s
+  id = %q
+}
+
+resource "faxter_security_group" %q {
+  project      = %q
+  name         = %q
+  manage_rules = false
+}
+`, label, project+"/"+name, label, project, name)
+
+	for _, rule := range rules {
+		ruleLabel := terraformLabel(name + "_" + rule.ID)
+		fmt.Fprintf(w, `
+import {
+  to = faxter_security_group_rule.%s
+  id = %q
+}
+
+resource "faxter_security_group_rule" %q {
+`, ruleLabel, project+"/"+name+"/"+rule.ID, ruleLabel)
+		writeArguments(w, [][2]string{
+			{"project", fmt.Sprintf("%q", project)},
+			{"security_group", fmt.Sprintf("faxter_security_group.%s.name", label)},
+			{"protocol", quoteIfSet(rule.Protocol)},
+			{"port_range_min", intIfSet(rule.PortRangeMin)},
+			{"port_range_max", intIfSet(rule.PortRangeMax)},
+			{"direction", quoteIfSet(rule.Direction)},
+			{"remote_ip_prefix", quoteIfSet(rule.RemoteIpPrefix)},
+			{"remote_group_id", quoteIfSet(rule.RemoteGroupId)},
+			{"ether_type", quoteIfSet(rule.EtherType)},
+		})
+		fmt.Fprintln(w, "}")
+	}
+}
+
+// writeArguments writes the arguments with a value, aligned as terraform
+// fmt would.
+func writeArguments(w io.Writer, args [][2]string) {
+	width := 0
+	for _, arg := range args {
+		if arg[1] != "" && len(arg[0]) > width {
+			width = len(arg[0])
+		}
+	}
+	for _, arg := range args {
+		if arg[1] != "" {
+			fmt.Fprintf(w, "  %-*s = %s\n", width, arg[0], arg[1])
+		}
+	}
+}
+
+func quoteIfSet(s string) string {
+	if s == "" {
+		return ""
+	}
+	return fmt.Sprintf("%q", s)
+}
+
+func intIfSet(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprint(n)
+}
+
+var nonLabelChars = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// terraformLabel turns an API name into a valid resource label.
+func terraformLabel(name string) string {
+	label := nonLabelChars.ReplaceAllString(name, "_")
+	if label == "" || strings.IndexAny(label[:1], "0123456789-") == 0 {
+		label = "r_" + label
+	}
+	return label
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// requestHeadersAttribute is added to every resource to set extra request
+// headers for that resource's API calls, e.g. to route one resource's
+// traffic to a canary deployment.
+const requestHeadersAttribute = "request_headers"
+
+// withRequestHeaders adds the request_headers attribute to a resource and
+// wraps its CRUD functions so their API calls carry those headers on top of
+// the provider's extra_headers.
+func withRequestHeaders(r *schema.Resource) {
+	r.Schema[requestHeadersAttribute] = &schema.Schema{
+		Type:         schema.TypeMap,
+		Optional:     true,
+		Elem:         &schema.Schema{Type: schema.TypeString},
+		ValidateFunc: validateExtraHeaders,
+		Description:  "Headers added to this resource's API requests, overriding the provider's extra_headers of the same name.",
+	}
+	if r.UpdateContext == nil {
+		// The headers only affect later requests, so changing them on a
+		// resource that is otherwise immutable has nothing to send.
+		r.UpdateContext = func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			return nil
+		}
+	}
+	r.CreateContext = scopeHeaders(r.CreateContext)
+	r.ReadContext = scopeHeaders(r.ReadContext)
+	r.UpdateContext = scopeHeaders(r.UpdateContext)
+	r.DeleteContext = scopeHeaders(r.DeleteContext)
+}
+
+func scopeHeaders[F ~func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics](f F) F {
+	if f == nil {
+		return nil
+	}
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		headers, _ := d.Get(requestHeadersAttribute).(map[string]interface{})
+		if len(headers) == 0 {
+			return f(ctx, d, m)
+		}
+
+		overrides := http.Header{}
+		for k, v := range headers {
+			overrides.Set(k, v.(string))
+		}
+		return f(ctx, d, m.(*Client).withHeaders(overrides))
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// limitTransport makes every API call of the provider share one budget, so a
+// highly parallel apply stays under the API's rate limits. Requests are spaced
+// at least interval apart, and at most cap of them are in flight at once; a
+// slot is held until the response body is closed. A zero interval or cap
+// disables that limit.
+type limitTransport struct {
+	interval time.Duration
+	slots    chan struct{}
+	base     http.RoundTripper
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+func newLimitTransport(requestsPerSecond float64, maxConcurrent int, base http.RoundTripper) *limitTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	t := &limitTransport{base: base}
+	if requestsPerSecond > 0 {
+		t.interval = time.Duration(float64(time.Second) / requestsPerSecond)
+	}
+	if maxConcurrent > 0 {
+		t.slots = make(chan struct{}, maxConcurrent)
+	}
+	return t
+}
+
+func (t *limitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	if t.slots != nil {
+		select {
+		case t.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if err := t.wait(ctx); err != nil {
+		t.release()
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		t.release()
+		return nil, err
+	}
+	resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: t.release}
+	return resp, nil
+}
+
+// wait blocks until the request's turn under the rate limit.
+func (t *limitTransport) wait(ctx context.Context) error {
+	if t.interval == 0 {
+		return nil
+	}
+
+	t.mu.Lock()
+	now := time.Now()
+	turn := t.next
+	if turn.Before(now) {
+		turn = now
+	}
+	t.next = turn.Add(t.interval)
+	t.mu.Unlock()
+
+	delay := time.Until(turn)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (t *limitTransport) release() {
+	if t.slots != nil {
+		<-t.slots
+	}
+}
+
+// releaseOnClose frees a concurrency slot once, when the body is closed.
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+	once    sync.Once
+}
+
+func (b *releaseOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// lockCapability is reported by APIs that support locking servers and
+// volumes against deletion.
+const lockCapability = "resource_locks"
+
+// lockSchema is the lock attribute shared by lockable resources.
+func lockSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Default:     false,
+		Description: "Lock the object so it cannot be deleted from the console or API. The provider releases the lock itself when Terraform destroys the object.",
+	}
+}
+
+// setLock places or releases the lock on a server or volume.
+func setLock(ctx context.Context, c *Client, collection, project, name string, locked bool) error {
+	if err := c.requireCapability(lockCapability, "lock"); err != nil {
+		return err
+	}
+	if locked {
+		if err := c.api.Lock(ctx, collection, project, name, "managed by Terraform"); err != nil {
+			return fmt.Errorf("failed to lock '%s': %w", name, err)
+		}
+		return nil
+	}
+	if err := c.api.Unlock(ctx, collection, project, name); err != nil && !faxter.IsNotFound(err) {
+		return fmt.Errorf("failed to unlock '%s': %w", name, err)
+	}
+	return nil
+}
+
+// readLock records whether a server or volume is locked. Nothing is read
+// from APIs without lock support, where lock can only be false.
+func readLock(ctx context.Context, c *Client, d *schema.ResourceData, collection string) error {
+	if !c.supports(lockCapability) {
+		return nil
+	}
+	lock, err := c.api.GetLock(ctx, collection, d.Get("project").(string), d.Id())
+	if faxter.IsNotFound(err) {
+		return d.Set("lock", false)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read lock: %w", err)
+	}
+	return d.Set("lock", lock.Locked)
+}
+
+// releaseLock removes any lock on a server or volume that Terraform is about
+// to destroy, whether it was placed through lock, a faxter_resource_lock or
+// the console. Locks guard against deletion outside Terraform; a planned
+// destroy has already been reviewed.
+func releaseLock(ctx context.Context, c *Client, collection, project, name string) error {
+	if !c.supports(lockCapability) {
+		return nil
+	}
+	err := c.api.Unlock(ctx, collection, project, name)
+	if err != nil && !faxter.IsNotFound(err) {
+		return fmt.Errorf("failed to release lock on '%s' before deleting it: %w", name, err)
+	}
+	if err == nil {
+		tflog.Debug(ctx, "Released lock before delete", map[string]interface{}{
+			"collection": collection,
+			"name":       name,
+		})
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// maxLoggedBody caps how much of a request or response body is logged.
+const maxLoggedBody = 4096
+
+// redactedKeys are JSON fields whose values never appear in logs.
+var redactedKeys = map[string]bool{
+	"token":         true,
+	"access_token":  true,
+	"refresh_token": true,
+	"password":      true,
+	"secret":        true,
+	"private_key":   true,
+	"cloud_init":    true,
+	"user_data":     true,
+}
+
+// loggingTransport logs every API call through tflog so TF_LOG=DEBUG shows
+// method, URL, status and latency, and TF_LOG=TRACE adds the request and
+// response bodies with credentials redacted. Headers are never logged.
+type loggingTransport struct {
+	base http.RoundTripper
+
+	// trace is set when bodies are logged. Otherwise they are left alone,
+	// so that a response is only ever read by its consumer.
+	trace bool
+}
+
+func newLoggingTransport(base http.RoundTripper) *loggingTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &loggingTransport{base: base, trace: traceLogging()}
+}
+
+// traceLogging reports whether the provider logs at TRACE, from the
+// environment Terraform passes to it.
+func traceLogging() bool {
+	level := os.Getenv("TF_LOG_PROVIDER")
+	if level == "" {
+		level = os.Getenv("TF_LOG")
+	}
+	// TF_LOG=JSON logs at TRACE in JSON.
+	return strings.EqualFold(level, "TRACE") || strings.EqualFold(level, "JSON")
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	// Only JSON bodies are logged; an upload is left to stream.
+	var reqBody []byte
+	if t.trace && req.Body != nil && strings.HasPrefix(req.Header.Get("Content-Type"), "application/json") {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		// A RoundTripper must not modify the caller's request.
+		req = req.Clone(ctx)
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+		req.ContentLength = int64(len(reqBody))
+	}
+
+	fields := map[string]interface{}{
+		"method": req.Method,
+		"url":    req.URL.String(),
+	}
+	if reqBody != nil {
+		tflog.Trace(ctx, "API request body", mergeFields(fields, "body", redactBody(reqBody, false)))
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	fields["latency_ms"] = time.Since(start).Milliseconds()
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.Debug(ctx, "API request failed", fields)
+		return nil, err
+	}
+
+	fields["status"] = resp.StatusCode
+	if requestID := resp.Header.Get("X-Request-Id"); requestID != "" {
+		fields["request_id"] = requestID
+	}
+	tflog.Debug(ctx, "API request", fields)
+
+	if !t.trace {
+		return resp, nil
+	}
+
+	// Log no more of the body than is shown, and hand the consumer the
+	// logged prefix followed by the rest of the stream, which is left to
+	// the consumer's own limits.
+	prefix, err := io.ReadAll(io.LimitReader(resp.Body, maxLoggedBody+1))
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(prefix), resp.Body), resp.Body}
+	tflog.Trace(ctx, "API response body", mergeFields(fields, "body", redactBody(prefix, len(prefix) > maxLoggedBody)))
+
+	return resp, nil
+}
+
+func mergeFields(fields map[string]interface{}, key string, value interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(fields)+1)
+	for k, v := range fields {
+		merged[k] = v
+	}
+	merged[key] = value
+	return merged
+}
+
+// redactBody returns a body for logging: JSON has sensitive fields masked,
+// anything else is passed through. Long bodies are truncated. A truncated
+// body is no longer valid JSON, so its sensitive fields are masked by
+// pattern instead.
+func redactBody(body []byte, truncated bool) string {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err == nil {
+		if redacted, err := json.Marshal(redactValue(v)); err == nil {
+			body = redacted
+		}
+	} else {
+		body = redactedPattern.ReplaceAll(body, []byte(`"$1":"***"`))
+	}
+
+	s := string(body)
+	if len(s) > maxLoggedBody {
+		s = s[:maxLoggedBody]
+		truncated = true
+	}
+	if truncated {
+		s += "...(truncated)"
+	}
+	return s
+}
+
+// redactedPattern matches a string field named in redactedKeys, including
+// one cut off by truncation.
+var redactedPattern = func() *regexp.Regexp {
+	keys := make([]string, 0, len(redactedKeys))
+	for k := range redactedKeys {
+		keys = append(keys, regexp.QuoteMeta(k))
+	}
+	sort.Strings(keys)
+	return regexp.MustCompile(`(?i)"(` + strings.Join(keys, "|") + `)"\s*:\s*"(?:[^"\\]|\\.)*(?:"|\\?$)`)
+}()
+
+func redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, inner := range v {
+			if redactedKeys[strings.ToLower(k)] {
+				v[k] = "***"
+			} else {
+				v[k] = redactValue(inner)
+			}
+		}
+	case []interface{}:
+		for i, inner := range v {
+			v[i] = redactValue(inner)
+		}
+	}
+	return v
+}
@@ -1,11 +1,33 @@
 package main
 
 import (
+  "context"
+  "fmt"
+  "os"
+
   "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
   "github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
 )
 
+// version is set at build time with -ldflags "-X main.version=...".
+var version = "dev"
+
 func main() {
+  // "generate" runs the import discovery command instead of serving the
+  // plugin to Terraform.
+  if len(os.Args) > 1 && os.Args[1] == "generate" {
+    os.Exit(runGenerate(os.Args[2:], os.Stdout, os.Stderr))
+  }
+
+  ctx := context.Background()
+  shutdownTracing, err := setupTracing(ctx)
+  if err != nil {
+    fmt.Fprintln(os.Stderr, err)
+    os.Exit(1)
+  }
+  // Flush spans once Terraform is done with the plugin.
+  defer shutdownTracing(ctx)
+
   plugin.Serve(&plugin.ServeOpts{
     ProviderFunc: func() *schema.Provider {
       return Provider()
@@ -0,0 +1,49 @@
+package faxter
+
+import "context"
+
+// RefreshTokenPath exchanges a refresh token for a new bearer token.
+const RefreshTokenPath = "/auth/refresh"
+
+// OIDCExchangePath exchanges an OIDC identity token, e.g. one issued to a CI
+// job, for a bearer token. The response has the shape of a token refresh.
+const OIDCExchangePath = "/auth/oidc"
+
+// Whoami returns the identity behind the client's token.
+func (c *Client) Whoami(ctx context.Context) (*WhoamiResponse, error) {
+	var whoami WhoamiResponse
+	if err := c.Do(ctx, "GET", "/whoami", nil, &whoami); err != nil {
+		return nil, err
+	}
+	return &whoami, nil
+}
+
+// RefreshToken exchanges refreshToken for a new access token. It does not
+// change c.Token.
+func (c *Client) RefreshToken(ctx context.Context, refreshToken string) (*TokenRefreshResponse, error) {
+	var refreshed TokenRefreshResponse
+	if err := c.Do(ctx, "POST", RefreshTokenPath, &TokenRefreshRequest{RefreshToken: refreshToken}, &refreshed); err != nil {
+		return nil, err
+	}
+	return &refreshed, nil
+}
+
+// ExchangeOIDCToken exchanges an OIDC identity token for an access token. It
+// does not change c.Token.
+func (c *Client) ExchangeOIDCToken(ctx context.Context, token, audience string) (*TokenRefreshResponse, error) {
+	var exchanged TokenRefreshResponse
+	if err := c.Do(ctx, "POST", OIDCExchangePath, &OIDCExchangeRequest{Token: token, Audience: audience}, &exchanged); err != nil {
+		return nil, err
+	}
+	return &exchanged, nil
+}
+
+// GetVersion returns the API version and capabilities. Deployments older
+// than the version endpoint answer 404.
+func (c *Client) GetVersion(ctx context.Context) (*VersionResponse, error) {
+	var version VersionResponse
+	if err := c.Do(ctx, "GET", "/version", nil, &version); err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
@@ -0,0 +1,35 @@
+package faxter
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+func billingAlertPath(project, name string) string {
+	return fmt.Sprintf("/billing/alerts/%s?project_name=%s", url.PathEscape(name), url.QueryEscape(project))
+}
+
+// CreateBillingAlert creates a spend alert for a project.
+func (c *Client) CreateBillingAlert(ctx context.Context, req *BillingAlertRequest) error {
+	return c.Do(ctx, "POST", "/billing/alerts/", req, nil)
+}
+
+// GetBillingAlert returns the named alert.
+func (c *Client) GetBillingAlert(ctx context.Context, project, name string) (*BillingAlertResponse, error) {
+	var alert BillingAlertResponse
+	if err := c.Do(ctx, "GET", billingAlertPath(project, name), nil, &alert); err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
+
+// UpdateBillingAlert replaces an alert's threshold and channel.
+func (c *Client) UpdateBillingAlert(ctx context.Context, project, name string, req *BillingAlertRequest) error {
+	return c.Do(ctx, "PUT", billingAlertPath(project, name), req, nil)
+}
+
+// DeleteBillingAlert deletes the named alert.
+func (c *Client) DeleteBillingAlert(ctx context.Context, project, name string) error {
+	return c.Do(ctx, "DELETE", billingAlertPath(project, name), nil, nil)
+}
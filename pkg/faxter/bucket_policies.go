@@ -0,0 +1,30 @@
+package faxter
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+func bucketPolicyPath(project, bucket string) string {
+	return fmt.Sprintf("/object_storage/buckets/%s/policy?project_name=%s", url.PathEscape(bucket), url.QueryEscape(project))
+}
+
+// PutBucketPolicy sets the access policy of an object storage bucket.
+func (c *Client) PutBucketPolicy(ctx context.Context, project, bucket string, req *BucketPolicyRequest) error {
+	return c.Do(ctx, "PUT", bucketPolicyPath(project, bucket), req, nil)
+}
+
+// GetBucketPolicy returns the access policy of an object storage bucket.
+func (c *Client) GetBucketPolicy(ctx context.Context, project, bucket string) (*BucketPolicyResponse, error) {
+	var policy BucketPolicyResponse
+	if err := c.Do(ctx, "GET", bucketPolicyPath(project, bucket), nil, &policy); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// DeleteBucketPolicy removes the access policy of an object storage bucket.
+func (c *Client) DeleteBucketPolicy(ctx context.Context, project, bucket string) error {
+	return c.Do(ctx, "DELETE", bucketPolicyPath(project, bucket), nil, nil)
+}
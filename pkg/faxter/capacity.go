@@ -0,0 +1,27 @@
+package faxter
+
+import (
+	"context"
+	"net/url"
+)
+
+// CapacityPath returns the path reporting free capacity per availability
+// zone, optionally limited to one flavor.
+func CapacityPath(project, flavor string) string {
+	query := url.Values{}
+	query.Set("project_name", project)
+	if flavor != "" {
+		query.Set("flavor", flavor)
+	}
+	return "/capacity?" + query.Encode()
+}
+
+// GetCapacity returns the free capacity per availability zone. Deployments
+// that do not publish capacity answer 404 or 501.
+func (c *Client) GetCapacity(ctx context.Context, project, flavor string) (*CapacityResponse, error) {
+	var capacity CapacityResponse
+	if err := c.Do(ctx, "GET", CapacityPath(project, flavor), nil, &capacity); err != nil {
+		return nil, err
+	}
+	return &capacity, nil
+}
@@ -0,0 +1,194 @@
+// Package faxter is a client for the Faxter cloud API. The Terraform provider
+// is built on it, and other Go programs can import it to manage the same
+// objects without going through Terraform.
+package faxter
+
+//go:generate go run gen.go -spec openapi.json -out models_gen.go
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// DefaultBaseURL is the endpoint of the hosted Faxter API.
+const DefaultBaseURL = "https://api.faxter.com"
+
+// Client sends authenticated requests to the Faxter API.
+type Client struct {
+	// BaseURL is the API endpoint, without a trailing slash.
+	BaseURL string
+
+	// Token is sent as a bearer token when set.
+	Token string
+
+	// UserAgent identifies the calling program to the API.
+	UserAgent string
+
+	// Headers are added to every request, e.g. for a gateway in front of
+	// the API. They cannot replace Authorization, Content-Type or User-Agent.
+	Headers http.Header
+
+	// HTTPClient sends the requests. Callers may replace its Transport to
+	// add retries, logging or request signing.
+	HTTPClient *http.Client
+
+	// PageSize is the number of items requested per page from list
+	// endpoints. Zero means DefaultPageSize.
+	PageSize int
+
+	// StrictDecoding makes decoding a response fail when it has a field
+	// the models don't know, or lacks one they require, rather than
+	// ignoring or zeroing it.
+	StrictDecoding bool
+}
+
+// NewClient returns a client for the API at baseURL.
+func NewClient(baseURL, token string) *Client {
+	return &Client{
+		BaseURL:    baseURL,
+		Token:      token,
+		UserAgent:  "faxter-go",
+		HTTPClient: &http.Client{Transport: NewTransport()},
+	}
+}
+
+// NewRequest builds an authenticated request for path, which is relative to
+// the base URL and may include a query string.
+func (c *Client) NewRequest(method, path string) (*http.Request, error) {
+	req, err := http.NewRequest(method, c.BaseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range c.Headers {
+		req.Header[k] = append([]string(nil), v...)
+	}
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.Token))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// Error is returned when the API answers with a status other than 200 OK.
+type Error struct {
+	StatusCode int
+	Status     string
+	Body       string
+
+	// Code is the API's machine-readable error code, e.g. "quota_exceeded",
+	// when the body carries one.
+	Code string
+
+	// RequestID is the response's X-Request-Id header. Faxter support can
+	// find the request in their logs by it.
+	RequestID string
+}
+
+// NewError reads resp, a response with a status other than 200 OK, into an
+// Error. It consumes resp.Body but leaves closing it to the caller.
+func NewError(resp *http.Response) *Error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+	e := &Error{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Body:       string(body),
+		RequestID:  resp.Header.Get("X-Request-Id"),
+	}
+	var parsed struct {
+		Code string `json:"code"`
+	}
+	if json.Unmarshal(body, &parsed) == nil {
+		e.Code = parsed.Code
+	}
+	return e
+}
+
+func (e *Error) Error() string {
+	if detail := e.Detail(); detail != "" {
+		return fmt.Sprintf("%s - %s", e.Status, detail)
+	}
+	if e.Body == "" {
+		return e.Status
+	}
+	return fmt.Sprintf("%s - %s", e.Status, e.Body)
+}
+
+// Detail returns the "detail" message of a JSON error body, or "" when the
+// body has none.
+func (e *Error) Detail() string {
+	var body struct {
+		Detail string `json:"detail"`
+	}
+	if json.Unmarshal([]byte(e.Body), &body) != nil {
+		return ""
+	}
+	return body.Detail
+}
+
+// IsNotFound reports whether err is an API error for a missing object.
+func IsNotFound(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// IsUnauthorized reports whether err is an API error rejecting the client's
+// credentials.
+func IsUnauthorized(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden)
+}
+
+// Do sends a request to path. A non-nil in is sent as the JSON body, and a
+// non-nil out receives the decoded JSON response.
+func (c *Client) Do(ctx context.Context, method, path string, in, out interface{}) error {
+	req, err := c.NewRequest(method, path)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		bodyBytes, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+		req.ContentLength = int64(len(bodyBytes))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(bodyBytes)), nil
+		}
+	}
+
+	resp, err := c.HTTPClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return NewError(resp)
+	}
+
+	if out == nil {
+		return nil
+	}
+	return c.DecodeResponse(resp, out)
+}
+
+// ObjectPath returns the path of a named object in a project-scoped
+// collection.
+func ObjectPath(collection, project, name string) string {
+	return fmt.Sprintf("/%s/%s?project_name=%s", collection, url.PathEscape(name), url.QueryEscape(project))
+}
+
+// collectionPath returns the path listing a project-scoped collection.
+func collectionPath(collection, project string) string {
+	return fmt.Sprintf("/%s/?project_name=%s", collection, url.QueryEscape(project))
+}
@@ -0,0 +1,107 @@
+package faxter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+const (
+	// maxResponseBytes bounds how much of a successful response is read, so
+	// that a misbehaving proxy can't exhaust memory.
+	maxResponseBytes = 32 << 20
+
+	// maxErrorBodyBytes bounds how much of an error response is kept.
+	maxErrorBodyBytes = 64 << 10
+
+	// snippetBytes is how much of an undecodable body an error quotes.
+	snippetBytes = 200
+)
+
+// DecodeError is returned when a response that should carry JSON doesn't,
+// typically because a proxy or load balancer answered with an HTML page.
+type DecodeError struct {
+	Status      string
+	ContentType string
+
+	// Snippet is the start of the body, with whitespace collapsed.
+	Snippet string
+
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	contentType := e.ContentType
+	if contentType == "" {
+		contentType = "no content type"
+	}
+	return fmt.Sprintf("unexpected response from the API (%s, %s): %s; the body begins: %q", e.Status, contentType, e.Err, e.Snippet)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeResponse reads the JSON body of resp into out. The body must be
+// labelled as JSON, or not labelled at all, and fit in the response size
+// limit. When it can't be decoded the error quotes the start of the body.
+func DecodeResponse(resp *http.Response, out interface{}) error {
+	return decodeResponse(resp, out, false)
+}
+
+func decodeResponse(resp *http.Response, out interface{}, strict bool) error {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes+1))
+	if err != nil {
+		return err
+	}
+	fail := func(err error) error {
+		return &DecodeError{
+			Status:      resp.Status,
+			ContentType: resp.Header.Get("Content-Type"),
+			Snippet:     snippet(body),
+			Err:         err,
+		}
+	}
+
+	if len(body) > maxResponseBytes {
+		return fail(fmt.Errorf("the body exceeds %d MiB", maxResponseBytes>>20))
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || !isJSONMediaType(mediaType) {
+			return fail(errors.New("expected a JSON body"))
+		}
+	}
+
+	// Some gateways prefix a byte order mark, which the JSON decoder
+	// rejects.
+	body = bytes.TrimPrefix(body, []byte("\xef\xbb\xbf"))
+	if err := json.Unmarshal(body, out); err != nil {
+		return fail(err)
+	}
+	if strict {
+		if err := checkFields(body, reflect.TypeOf(out), ""); err != nil {
+			return fail(err)
+		}
+	}
+	return nil
+}
+
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// snippet returns the start of body for an error message.
+func snippet(body []byte) string {
+	s := strings.Join(strings.Fields(string(body)), " ")
+	if len(s) > snippetBytes {
+		s = s[:snippetBytes] + "..."
+	}
+	return s
+}
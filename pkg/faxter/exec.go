@@ -0,0 +1,34 @@
+package faxter
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+func execPath(project, server, id string) string {
+	path := fmt.Sprintf("/servers/%s/exec", url.PathEscape(server))
+	if id != "" {
+		path += "/" + url.PathEscape(id)
+	}
+	return path + "?project_name=" + url.QueryEscape(project)
+}
+
+// StartExec runs a command on a server through its agent. It returns once
+// the command has started; poll GetExec for the result.
+func (c *Client) StartExec(ctx context.Context, project, server string, req *ExecRequest) (*ExecResponse, error) {
+	var exec ExecResponse
+	if err := c.Do(ctx, "POST", execPath(project, server, ""), req, &exec); err != nil {
+		return nil, err
+	}
+	return &exec, nil
+}
+
+// GetExec returns the status and output of a command started by StartExec.
+func (c *Client) GetExec(ctx context.Context, project, server, id string) (*ExecResponse, error) {
+	var exec ExecResponse
+	if err := c.Do(ctx, "GET", execPath(project, server, id), nil, &exec); err != nil {
+		return nil, err
+	}
+	return &exec, nil
+}
@@ -0,0 +1,27 @@
+package faxter
+
+import "context"
+
+// CreateGatewayService creates a NAT gateway for a network.
+func (c *Client) CreateGatewayService(ctx context.Context, req *GatewayServiceRequest) error {
+	return c.Do(ctx, "POST", "/gateway_services/", req, nil)
+}
+
+// GetGatewayService returns the named gateway.
+func (c *Client) GetGatewayService(ctx context.Context, project, name string) (*GatewayServiceResponse, error) {
+	var gateway GatewayServiceResponse
+	if err := c.Do(ctx, "GET", ObjectPath("gateway_services", project, name), nil, &gateway); err != nil {
+		return nil, err
+	}
+	return &gateway, nil
+}
+
+// UpdateGatewayService changes a gateway's bandwidth tier.
+func (c *Client) UpdateGatewayService(ctx context.Context, project, name string, req *GatewayServiceUpdateRequest) error {
+	return c.Do(ctx, "PUT", ObjectPath("gateway_services", project, name), req, nil)
+}
+
+// DeleteGatewayService deletes the named gateway.
+func (c *Client) DeleteGatewayService(ctx context.Context, project, name string) error {
+	return c.Do(ctx, "DELETE", ObjectPath("gateway_services", project, name), nil, nil)
+}
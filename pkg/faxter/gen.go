@@ -0,0 +1,301 @@
+//go:build ignore
+
+// gen.go writes the request and response models in models_gen.go from the
+// schemas of the Faxter OpenAPI document. To pick up API changes, refresh the
+// checked-in copy and regenerate:
+//
+//	curl -o openapi.json https://api.faxter.com/openapi.json
+//	go generate ./pkg/faxter
+//
+// Only the subset of OpenAPI the API uses is understood: objects, arrays,
+// string maps, scalars, $ref, allOf (rendered as embedding) and nullable
+// (rendered as a pointer). Anything else fails generation rather than being
+// guessed at.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"sort"
+	"strings"
+)
+
+// initialisms are spelled in upper case, or as given, in Go names.
+var initialisms = map[string]string{
+	"api":   "API",
+	"cidr":  "CIDR",
+	"cidrs": "CIDRs",
+	"cpu":   "CPU",
+	"dns":   "DNS",
+	"dscp":  "DSCP",
+	"gb":    "GB",
+	"id":    "ID",
+	"ip":    "IP",
+	"mac":   "MAC",
+	"oidc":  "OIDC",
+	"ptr":   "PTR",
+	"qos":   "QoS",
+	"ram":   "RAM",
+	"ssh":   "SSH",
+	"ssl":   "SSL",
+	"url":   "URL",
+	"vcpus": "VCPUs",
+	"vm":    "VM",
+}
+
+// fieldNames keeps the Go names of fields that predate the generator, keyed
+// by "<schema>.<property>", so that regenerating doesn't break callers.
+var fieldNames = map[string]string{
+	"SecurityGroupRuleRequest.remote_ip_prefix": "RemoteIpPrefix",
+	"SecurityGroupRuleRequest.remote_group_id":  "RemoteGroupId",
+	"ServerProperties.request_floating_ip":      "RequestFloating",
+	"ServerUpdateRequest.subnetworks":           "SubNetworks",
+}
+
+// rawProperties lists the schemas that also keep their properties object
+// undecoded, for fields the document doesn't describe. The UnmarshalJSON
+// filling it is written by hand.
+var rawProperties = map[string]bool{
+	"LoadBalancerResponse": true,
+	"ResourceResponse":     true,
+}
+
+type document struct {
+	Components struct {
+		Schemas map[string]*schemaObject `json:"schemas"`
+	} `json:"components"`
+}
+
+type schemaObject struct {
+	Ref                  string          `json:"$ref"`
+	Type                 string          `json:"type"`
+	Description          string          `json:"description"`
+	Nullable             bool            `json:"nullable"`
+	Properties           properties      `json:"properties"`
+	Required             []string        `json:"required"`
+	Items                *schemaObject   `json:"items"`
+	AdditionalProperties *schemaObject   `json:"additionalProperties"`
+	AllOf                []*schemaObject `json:"allOf"`
+}
+
+// properties keeps the order of an object's properties, which becomes the
+// order of the struct fields.
+type properties []property
+
+type property struct {
+	name   string
+	schema *schemaObject
+}
+
+func (p *properties) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		var s schemaObject
+		if err := dec.Decode(&s); err != nil {
+			return err
+		}
+		*p = append(*p, property{name: tok.(string), schema: &s})
+	}
+	return nil
+}
+
+type generator struct {
+	buf     bytes.Buffer
+	imports map[string]bool
+}
+
+func main() {
+	spec := flag.String("spec", "openapi.json", "OpenAPI document to read")
+	out := flag.String("out", "models_gen.go", "Go file to write")
+	flag.Parse()
+
+	data, err := os.ReadFile(*spec)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var doc document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		log.Fatalf("%s: %s", *spec, err)
+	}
+
+	names := make([]string, 0, len(doc.Components.Schemas))
+	for name := range doc.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	g := &generator{imports: map[string]bool{}}
+	for _, name := range names {
+		if err := g.writeSchema(name, doc.Components.Schemas[name]); err != nil {
+			log.Fatalf("schema %s: %s", name, err)
+		}
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by gen.go from %s; DO NOT EDIT.\n\npackage faxter\n\n", *spec)
+	if len(g.imports) > 0 {
+		src.WriteString("import (\n")
+		for _, path := range sortedKeys(g.imports) {
+			fmt.Fprintf(&src, "\t%q\n", path)
+		}
+		src.WriteString(")\n\n")
+	}
+	src.Write(g.buf.Bytes())
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		log.Fatalf("formatting generated code: %s", err)
+	}
+	if err := os.WriteFile(*out, formatted, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (g *generator) writeSchema(name string, s *schemaObject) error {
+	writeComment(&g.buf, "", s.Description)
+	fmt.Fprintf(&g.buf, "type %s struct {\n", name)
+
+	parts := []*schemaObject{s}
+	if len(s.AllOf) > 0 {
+		parts = s.AllOf
+	}
+	for _, part := range parts {
+		if part.Ref != "" {
+			fmt.Fprintf(&g.buf, "\t%s\n", refName(part.Ref))
+			continue
+		}
+		if part.Type != "object" {
+			return fmt.Errorf("unsupported type %q", part.Type)
+		}
+		required := map[string]bool{}
+		for _, r := range part.Required {
+			required[r] = true
+		}
+		for _, p := range part.Properties {
+			typ, err := g.goType(p.schema)
+			if err != nil {
+				return fmt.Errorf("property %s: %w", p.name, err)
+			}
+			tag := p.name
+			if !required[p.name] {
+				tag += ",omitempty"
+			}
+			writeComment(&g.buf, "\t", p.schema.Description)
+			fmt.Fprintf(&g.buf, "\t%s %s `json:%q`\n", fieldName(name, p.name), typ, tag)
+		}
+	}
+
+	if rawProperties[name] {
+		g.imports["encoding/json"] = true
+		g.buf.WriteString("\n\t// RawProperties holds the properties object as returned, including\n\t// fields not modelled above.\n")
+		g.buf.WriteString("\tRawProperties json.RawMessage `json:\"-\"`\n")
+	}
+	g.buf.WriteString("}\n\n")
+	return nil
+}
+
+func (g *generator) goType(s *schemaObject) (string, error) {
+	var typ string
+	switch {
+	case s.Ref != "":
+		typ = refName(s.Ref)
+	case len(s.AllOf) == 1 && s.AllOf[0].Ref != "":
+		// The OpenAPI 3.0 spelling of a nullable reference.
+		typ = refName(s.AllOf[0].Ref)
+	case s.Type == "string":
+		typ = "string"
+	case s.Type == "integer":
+		typ = "int"
+	case s.Type == "number":
+		typ = "float64"
+	case s.Type == "boolean":
+		typ = "bool"
+	case s.Type == "array":
+		if s.Items == nil {
+			return "", fmt.Errorf("array without items")
+		}
+		elem, err := g.goType(s.Items)
+		if err != nil {
+			return "", err
+		}
+		typ = "[]" + elem
+	case s.Type == "object" && s.AdditionalProperties != nil && len(s.Properties) == 0:
+		elem, err := g.goType(s.AdditionalProperties)
+		if err != nil {
+			return "", err
+		}
+		typ = "map[string]" + elem
+	case s.Type == "":
+		// Any JSON value, passed through as is.
+		g.imports["encoding/json"] = true
+		typ = "json.RawMessage"
+	default:
+		return "", fmt.Errorf("unsupported type %q; describe inline objects as named schemas", s.Type)
+	}
+	if s.Nullable {
+		typ = "*" + typ
+	}
+	return typ, nil
+}
+
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+func fieldName(schema, property string) string {
+	if name, ok := fieldNames[schema+"."+property]; ok {
+		return name
+	}
+	var b strings.Builder
+	for _, word := range strings.Split(property, "_") {
+		if word == "" {
+			continue
+		}
+		if initialism, ok := initialisms[word]; ok {
+			b.WriteString(initialism)
+			continue
+		}
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return b.String()
+}
+
+// writeComment writes text as a Go comment wrapped at 77 columns.
+func writeComment(buf *bytes.Buffer, indent, text string) {
+	if text == "" {
+		return
+	}
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if line != "" && len(indent)+3+len(line)+1+len(word) > 77 {
+			fmt.Fprintf(buf, "%s// %s\n", indent, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	fmt.Fprintf(buf, "%s// %s\n", indent, line)
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
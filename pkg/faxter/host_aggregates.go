@@ -0,0 +1,36 @@
+package faxter
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+func hostAggregatePath(name string) string {
+	return fmt.Sprintf("/host_aggregates/%s", url.PathEscape(name))
+}
+
+// CreateHostAggregate creates a host aggregate. Aggregates are not scoped to
+// a project.
+func (c *Client) CreateHostAggregate(ctx context.Context, req *HostAggregateRequest) error {
+	return c.Do(ctx, "POST", "/host_aggregates/", req, nil)
+}
+
+// GetHostAggregate returns the named aggregate.
+func (c *Client) GetHostAggregate(ctx context.Context, name string) (*HostAggregateResponse, error) {
+	var aggregate HostAggregateResponse
+	if err := c.Do(ctx, "GET", hostAggregatePath(name), nil, &aggregate); err != nil {
+		return nil, err
+	}
+	return &aggregate, nil
+}
+
+// UpdateHostAggregate replaces an aggregate's hosts and metadata.
+func (c *Client) UpdateHostAggregate(ctx context.Context, name string, req *HostAggregateRequest) error {
+	return c.Do(ctx, "PUT", hostAggregatePath(name), req, nil)
+}
+
+// DeleteHostAggregate deletes the named aggregate.
+func (c *Client) DeleteHostAggregate(ctx context.Context, name string) error {
+	return c.Do(ctx, "DELETE", hostAggregatePath(name), nil, nil)
+}
@@ -0,0 +1,44 @@
+package faxter
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+func imageMembersPath(project, image string) string {
+	return fmt.Sprintf("/images/%s/members?project_name=%s", url.PathEscape(image), url.QueryEscape(project))
+}
+
+func imageMemberPath(project, image, member string) string {
+	return fmt.Sprintf("/images/%s/members/%s?project_name=%s", url.PathEscape(image), url.PathEscape(member), url.QueryEscape(project))
+}
+
+// CreateImageMember shares an image owned by project with another project.
+func (c *Client) CreateImageMember(ctx context.Context, project, image, member string) (*ImageMemberResponse, error) {
+	var imageMember ImageMemberResponse
+	if err := c.Do(ctx, "POST", imageMembersPath(project, image), &ImageMemberRequest{MemberProject: member}, &imageMember); err != nil {
+		return nil, err
+	}
+	return &imageMember, nil
+}
+
+// GetImageMember returns the sharing of an image with a member project.
+func (c *Client) GetImageMember(ctx context.Context, project, image, member string) (*ImageMemberResponse, error) {
+	var imageMember ImageMemberResponse
+	if err := c.Do(ctx, "GET", imageMemberPath(project, image, member), nil, &imageMember); err != nil {
+		return nil, err
+	}
+	return &imageMember, nil
+}
+
+// SetImageMemberStatus accepts or rejects a shared image on behalf of the
+// member project, so the request is scoped to that project.
+func (c *Client) SetImageMemberStatus(ctx context.Context, image, member, status string) error {
+	return c.Do(ctx, "PUT", imageMemberPath(member, image, member), &ImageMemberUpdateRequest{Status: status}, nil)
+}
+
+// DeleteImageMember stops sharing an image with a member project.
+func (c *Client) DeleteImageMember(ctx context.Context, project, image, member string) error {
+	return c.Do(ctx, "DELETE", imageMemberPath(project, image, member), nil, nil)
+}
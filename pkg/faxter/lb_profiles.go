@@ -0,0 +1,29 @@
+package faxter
+
+import "context"
+
+// CreateLBProfile creates an LB profile.
+func (c *Client) CreateLBProfile(ctx context.Context, req *LBProfileRequest) error {
+	return c.Do(ctx, "POST", "/lb_profiles/", req, nil)
+}
+
+// GetLBProfile returns the named LB profile.
+func (c *Client) GetLBProfile(ctx context.Context, project, name string) (*LBProfileResponse, error) {
+	var profile LBProfileResponse
+	if err := c.Do(ctx, "GET", ObjectPath("lb_profiles", project, name), nil, &profile); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// UpdateLBProfile replaces an LB profile's settings. Load balancers using
+// the profile pick up the change.
+func (c *Client) UpdateLBProfile(ctx context.Context, project, name string, req *LBProfileRequest) error {
+	return c.Do(ctx, "PUT", ObjectPath("lb_profiles", project, name), req, nil)
+}
+
+// DeleteLBProfile deletes the named LB profile. The API refuses while load
+// balancers use it.
+func (c *Client) DeleteLBProfile(ctx context.Context, project, name string) error {
+	return c.Do(ctx, "DELETE", ObjectPath("lb_profiles", project, name), nil, nil)
+}
@@ -0,0 +1,58 @@
+package faxter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+func (r *LoadBalancerResponse) UnmarshalJSON(data []byte) error {
+	type plain LoadBalancerResponse
+	if err := json.Unmarshal(data, (*plain)(r)); err != nil {
+		return err
+	}
+	r.RawProperties = rawProperties(data)
+	return nil
+}
+
+// CreateLoadBalancer creates a load balancer.
+func (c *Client) CreateLoadBalancer(ctx context.Context, req *LoadBalancerCreateRequest) (*LoadBalancerResponse, error) {
+	var lb LoadBalancerResponse
+	if err := c.Do(ctx, "POST", "/loadbalancers/", req, &lb); err != nil {
+		return nil, err
+	}
+	return &lb, nil
+}
+
+// GetLoadBalancer returns the named load balancer.
+func (c *Client) GetLoadBalancer(ctx context.Context, project, name string) (*LoadBalancerResponse, error) {
+	var lb LoadBalancerResponse
+	if err := c.Do(ctx, "GET", ObjectPath("loadbalancers", project, name), nil, &lb); err != nil {
+		return nil, err
+	}
+	return &lb, nil
+}
+
+// UpdateLoadBalancer changes the fields set in req.
+func (c *Client) UpdateLoadBalancer(ctx context.Context, project, name string, req *LoadBalancerUpdateRequest) error {
+	return c.Do(ctx, "PUT", ObjectPath("loadbalancers", project, name), req, nil)
+}
+
+// DeleteLoadBalancer deletes the named load balancer.
+func (c *Client) DeleteLoadBalancer(ctx context.Context, project, name string) error {
+	return c.Do(ctx, "DELETE", ObjectPath("loadbalancers", project, name), nil, nil)
+}
+
+// AddLoadBalancerMember adds a backend to a load balancer.
+func (c *Client) AddLoadBalancerMember(ctx context.Context, project, name string, item ServerItem) error {
+	path := fmt.Sprintf("/loadbalancers/%s/members?project_name=%s", url.PathEscape(name), url.QueryEscape(project))
+	return c.Do(ctx, "POST", path, item, nil)
+}
+
+// RemoveLoadBalancerMember removes the backend at item's ip:port.
+func (c *Client) RemoveLoadBalancerMember(ctx context.Context, project, name string, item ServerItem) error {
+	member := fmt.Sprintf("%s:%d", item.IP, item.Port)
+	path := fmt.Sprintf("/loadbalancers/%s/members/%s?project_name=%s", url.PathEscape(name), url.PathEscape(member), url.QueryEscape(project))
+	return c.Do(ctx, "DELETE", path, nil, nil)
+}
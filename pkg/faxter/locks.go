@@ -0,0 +1,31 @@
+package faxter
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+func lockPath(collection, project, name string) string {
+	return fmt.Sprintf("/%s/%s/lock?project_name=%s", collection, url.PathEscape(name), url.QueryEscape(project))
+}
+
+// GetLock returns the lock on a named object in collection ("servers" or
+// "volumes").
+func (c *Client) GetLock(ctx context.Context, collection, project, name string) (*LockResponse, error) {
+	var lock LockResponse
+	if err := c.Do(ctx, "GET", lockPath(collection, project, name), nil, &lock); err != nil {
+		return nil, err
+	}
+	return &lock, nil
+}
+
+// Lock places a lock on a named object in collection, preventing its deletion.
+func (c *Client) Lock(ctx context.Context, collection, project, name, reason string) error {
+	return c.Do(ctx, "PUT", lockPath(collection, project, name), &LockRequest{Reason: reason}, nil)
+}
+
+// Unlock releases the lock on a named object in collection.
+func (c *Client) Unlock(ctx context.Context, collection, project, name string) error {
+	return c.Do(ctx, "DELETE", lockPath(collection, project, name), nil, nil)
+}
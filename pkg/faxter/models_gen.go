@@ -0,0 +1,602 @@
+// Code generated by gen.go from openapi.json; DO NOT EDIT.
+
+package faxter
+
+import (
+	"encoding/json"
+)
+
+// AllowedAddressPair lets a server interface send and receive traffic for an
+// address other than its own, e.g. a VRRP virtual IP, past the network's
+// anti-spoofing rules.
+type AllowedAddressPair struct {
+	Network string `json:"network"`
+	// IP address or CIDR.
+	IPAddress string `json:"ip_address"`
+	// MAC address the pair applies to. Defaults to the interface's own.
+	MACAddress string `json:"mac_address,omitempty"`
+}
+
+type AvailabilityZoneCapacity struct {
+	Name           string           `json:"name"`
+	VCPUsAvailable int              `json:"vcpus_available"`
+	RAMAvailableGB int              `json:"ram_available_gb"`
+	Flavors        []FlavorCapacity `json:"flavors"`
+}
+
+type BillingAlertRequest struct {
+	Project             string  `json:"project,omitempty"`
+	Name                string  `json:"name"`
+	MonthlyThreshold    float64 `json:"monthly_threshold"`
+	NotificationChannel string  `json:"notification_channel"`
+}
+
+type BillingAlertResponse struct {
+	Name                string  `json:"name"`
+	MonthlyThreshold    float64 `json:"monthly_threshold"`
+	NotificationChannel string  `json:"notification_channel"`
+}
+
+type BucketPolicyRequest struct {
+	Project string          `json:"project,omitempty"`
+	Policy  json.RawMessage `json:"policy"`
+}
+
+type BucketPolicyResponse struct {
+	Bucket string          `json:"bucket"`
+	Policy json.RawMessage `json:"policy"`
+}
+
+type CapacityResponse struct {
+	AvailabilityZones []AvailabilityZoneCapacity `json:"availability_zones"`
+}
+
+type EffectiveFlavor struct {
+	Name   string `json:"name"`
+	VCPUs  int    `json:"vcpus"`
+	RAMGB  int    `json:"ram_gb"`
+	DiskGB int    `json:"disk_gb"`
+}
+
+type EffectiveImage struct {
+	Name string `json:"name"`
+	ID   string `json:"id"`
+	// Build of the image the server was created from. It stays the same when
+	// the image name is later pointed at a newer build.
+	Build string `json:"build"`
+}
+
+// EncryptedUserData is user data sealed for the server's project: a random
+// AES-256-GCM key encrypts the data and RSA-OAEP-SHA256 encrypts that key
+// with the project's public key. Binary fields are base64-encoded.
+type EncryptedUserData struct {
+	KeyID        string `json:"key_id"`
+	Algorithm    string `json:"algorithm"`
+	EncryptedKey string `json:"encrypted_key"`
+	Nonce        string `json:"nonce"`
+	Ciphertext   string `json:"ciphertext"`
+}
+
+type ExecRequest struct {
+	Command string `json:"command"`
+	// Script, when set, is uploaded and run by the agent; Command then names
+	// the interpreter, e.g. "/bin/sh".
+	Script         string `json:"script,omitempty"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty"`
+}
+
+// ExecResponse describes a command run through the server agent. Status is
+// "running" until the command exits, then "completed" or "failed".
+type ExecResponse struct {
+	ID       string `json:"id"`
+	Status   string `json:"status"`
+	ExitCode *int   `json:"exit_code"`
+	Output   string `json:"output"`
+}
+
+type FlavorCapacity struct {
+	Name string `json:"name"`
+	// Number of servers of this flavor that can still be placed.
+	Available int `json:"available"`
+}
+
+type GatewayServiceRequest struct {
+	Project       string `json:"project,omitempty"`
+	Name          string `json:"name"`
+	Network       string `json:"network"`
+	BandwidthTier string `json:"bandwidth_tier,omitempty"`
+}
+
+type GatewayServiceResponse struct {
+	Name          string `json:"name"`
+	Network       string `json:"network"`
+	BandwidthTier string `json:"bandwidth_tier"`
+	ExternalIP    string `json:"external_ip"`
+	Status        string `json:"status"`
+}
+
+type GatewayServiceUpdateRequest struct {
+	BandwidthTier string `json:"bandwidth_tier"`
+}
+
+type HostAggregateRequest struct {
+	Name             string            `json:"name"`
+	AvailabilityZone string            `json:"availability_zone,omitempty"`
+	Hosts            []string          `json:"hosts"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
+}
+
+type HostAggregateResponse struct {
+	Name             string            `json:"name"`
+	AvailabilityZone string            `json:"availability_zone"`
+	Hosts            []string          `json:"hosts"`
+	Metadata         map[string]string `json:"metadata"`
+}
+
+type ImageMemberRequest struct {
+	MemberProject string `json:"member_project"`
+}
+
+// ImageMemberResponse describes a project an image is shared with. Status is
+// "pending" until the member project accepts or rejects the image.
+type ImageMemberResponse struct {
+	Image         string `json:"image"`
+	MemberProject string `json:"member_project"`
+	Status        string `json:"status"`
+}
+
+type ImageMemberUpdateRequest struct {
+	Status string `json:"status"`
+}
+
+// LBHealthCheck is how a load balancer probes its members.
+type LBHealthCheck struct {
+	// "tcp", "http" or "https".
+	Protocol string `json:"protocol"`
+	// Path requested by HTTP and HTTPS checks.
+	Path            string `json:"path,omitempty"`
+	IntervalSeconds int    `json:"interval_seconds"`
+	TimeoutSeconds  int    `json:"timeout_seconds"`
+	// Consecutive successes before a member is put back in rotation.
+	HealthyThreshold int `json:"healthy_threshold"`
+	// Consecutive failures before a member is taken out of rotation.
+	UnhealthyThreshold int `json:"unhealthy_threshold"`
+}
+
+// LBProfileRequest describes an LB profile: listener and pool settings
+// shared by the load balancers that name it. Zero values leave the API's
+// defaults in place.
+type LBProfileRequest struct {
+	Project string `json:"project,omitempty"`
+	Name    string `json:"name"`
+	// Idle timeout of client connections to the listener.
+	ClientTimeoutSeconds int `json:"client_timeout_seconds,omitempty"`
+	// Timeout for opening a connection to a member.
+	ConnectTimeoutSeconds int `json:"connect_timeout_seconds,omitempty"`
+	// Idle timeout of connections to members.
+	MemberTimeoutSeconds int `json:"member_timeout_seconds,omitempty"`
+	// Members tried before a request fails.
+	Retries     int            `json:"retries,omitempty"`
+	HealthCheck *LBHealthCheck `json:"health_check,omitempty"`
+}
+
+type LBProfileResponse struct {
+	Name                  string         `json:"name"`
+	ClientTimeoutSeconds  int            `json:"client_timeout_seconds"`
+	ConnectTimeoutSeconds int            `json:"connect_timeout_seconds"`
+	MemberTimeoutSeconds  int            `json:"member_timeout_seconds"`
+	Retries               int            `json:"retries"`
+	HealthCheck           *LBHealthCheck `json:"health_check"`
+}
+
+type LoadBalancerCreateRequest struct {
+	Project           string       `json:"project,omitempty"`
+	Name              string       `json:"name"`
+	Port              int          `json:"port,omitempty"`
+	Networks          []string     `json:"networks,omitempty"`
+	SubNetworks       []string     `json:"sub_networks,omitempty"`
+	KeyName           string       `json:"key_name,omitempty"`
+	RequestFloatingIP bool         `json:"request_floating_ip,omitempty"`
+	SSLEnabled        bool         `json:"ssl_enabled,omitempty"`
+	Certificate       string       `json:"certificate,omitempty"`
+	PrivateKey        string       `json:"private_key,omitempty"`
+	Servers           []ServerItem `json:"servers,omitempty"`
+	SecurityGroups    []string     `json:"security_groups,omitempty"`
+	AllowedCIDRs      []string     `json:"allowed_cidrs,omitempty"`
+	// Name of an LB profile supplying timeouts, retries and the health check.
+	Profile string `json:"profile,omitempty"`
+}
+
+type LoadBalancerProperties struct {
+	Profile string `json:"profile,omitempty"`
+}
+
+type LoadBalancerResponse struct {
+	Name       string                 `json:"name"`
+	Status     string                 `json:"status"`
+	Properties LoadBalancerProperties `json:"properties"`
+
+	// RawProperties holds the properties object as returned, including
+	// fields not modelled above.
+	RawProperties json.RawMessage `json:"-"`
+}
+
+type LoadBalancerUpdateRequest struct {
+	Name              string        `json:"name"`
+	Port              *int          `json:"port,omitempty"`
+	Networks          *[]string     `json:"networks,omitempty"`
+	SubNetworks       *[]string     `json:"sub_networks,omitempty"`
+	KeyName           *string       `json:"key_name,omitempty"`
+	RequestFloatingIP *bool         `json:"request_floating_ip,omitempty"`
+	SSLEnabled        *bool         `json:"ssl_enabled,omitempty"`
+	Certificate       *string       `json:"certificate,omitempty"`
+	PrivateKey        *string       `json:"private_key,omitempty"`
+	Servers           *[]ServerItem `json:"servers,omitempty"`
+	SecurityGroups    *[]string     `json:"security_groups,omitempty"`
+	AllowedCIDRs      *[]string     `json:"allowed_cidrs,omitempty"`
+	// Name of an LB profile, or "" to go back to the API's defaults.
+	Profile *string `json:"profile,omitempty"`
+}
+
+type LockRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// LockResponse describes the lock on a server or volume. A locked object
+// cannot be deleted, from the API or the console, until the lock is
+// released.
+type LockResponse struct {
+	Locked bool   `json:"locked"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type NetworkCreateRequest struct {
+	Project   string                `json:"project,omitempty"`
+	Name      string                `json:"name"`
+	Subnets   []SubnetCreateRequest `json:"subnets"`
+	QoSPolicy string                `json:"qos_policy,omitempty"`
+}
+
+type NetworkProperties struct {
+	Subnets []SubnetResponse `json:"subnets"`
+}
+
+type NetworkQoS struct {
+	Network            string `json:"network"`
+	BandwidthLimitMbps int    `json:"bandwidth_limit_mbps,omitempty"`
+	QoSPolicy          string `json:"qos_policy,omitempty"`
+}
+
+type NetworkResponse struct {
+	Name       string            `json:"name"`
+	Status     string            `json:"status"`
+	Properties NetworkProperties `json:"properties"`
+}
+
+type OIDCExchangeRequest struct {
+	Token    string `json:"token"`
+	Audience string `json:"audience,omitempty"`
+}
+
+// PlacementPolicyRequest describes where the servers that name a placement
+// policy are scheduled in relation to each other.
+type PlacementPolicyRequest struct {
+	Project string `json:"project,omitempty"`
+	Name    string `json:"name"`
+	// "spread" keeps the servers apart, "pack" puts them together.
+	Strategy string `json:"strategy"`
+	// What spread keeps the servers apart across: "host" (the default) or
+	// "availability_zone".
+	Scope string `json:"scope,omitempty"`
+	// Most servers of the policy on one host; zero for no limit.
+	MaxPerHost int `json:"max_per_host,omitempty"`
+}
+
+type PlacementPolicyResponse struct {
+	Name       string `json:"name"`
+	Strategy   string `json:"strategy"`
+	Scope      string `json:"scope"`
+	MaxPerHost int    `json:"max_per_host"`
+	// Servers placed under the policy.
+	Servers []string `json:"servers"`
+}
+
+type ProjectCreateRequest struct {
+	Name string `json:"name"`
+}
+
+// ProjectPublicKeyResponse is the key the API decrypts a project's encrypted
+// user data with.
+type ProjectPublicKeyResponse struct {
+	KeyID string `json:"key_id"`
+	// Encryption expected for the key, e.g. "RSA-OAEP-256+A256GCM".
+	Algorithm string `json:"algorithm"`
+	// PEM-encoded public key.
+	PublicKey string `json:"public_key"`
+}
+
+type QoSPolicyRequest struct {
+	Project          string `json:"project,omitempty"`
+	Name             string `json:"name"`
+	MaxBandwidthMbps int    `json:"max_bandwidth_mbps,omitempty"`
+	MaxBurstKbits    int    `json:"max_burst_kbits,omitempty"`
+	DSCPMark         *int   `json:"dscp_mark,omitempty"`
+}
+
+type QoSPolicyResponse struct {
+	Name             string `json:"name"`
+	MaxBandwidthMbps int    `json:"max_bandwidth_mbps"`
+	MaxBurstKbits    int    `json:"max_burst_kbits"`
+	DSCPMark         *int   `json:"dscp_mark"`
+}
+
+type QuotaRequestRequest struct {
+	Project       string `json:"project,omitempty"`
+	ResourceType  string `json:"resource_type"`
+	Amount        int    `json:"amount"`
+	Justification string `json:"justification"`
+}
+
+type QuotaRequestResponse struct {
+	ID            string `json:"id"`
+	ResourceType  string `json:"resource_type"`
+	Amount        int    `json:"amount"`
+	Justification string `json:"justification"`
+	Status        string `json:"status"`
+	ReviewComment string `json:"review_comment"`
+}
+
+// ResourceResponse is returned for servers, and on create for most other
+// resources.
+type ResourceResponse struct {
+	ID         string           `json:"id"`
+	Name       string           `json:"name"`
+	Status     string           `json:"status"`
+	Properties ServerProperties `json:"properties"`
+
+	// RawProperties holds the properties object as returned, including
+	// fields not modelled above.
+	RawProperties json.RawMessage `json:"-"`
+}
+
+type ReverseDNSRequest struct {
+	Project    string `json:"project,omitempty"`
+	FloatingIP string `json:"floating_ip"`
+	PTRRecord  string `json:"ptr_record"`
+}
+
+type ReverseDNSResponse struct {
+	FloatingIP string `json:"floating_ip"`
+	PTRRecord  string `json:"ptr_record"`
+}
+
+type RouteRule struct {
+	Destination string `json:"destination"`
+	Nexthop     string `json:"nexthop"`
+}
+
+type RouterCreateRequest struct {
+	Project         string   `json:"project,omitempty"`
+	Name            string   `json:"name"`
+	ConnectExternal bool     `json:"connect_external,omitempty"`
+	Subnets         []string `json:"subnets"`
+}
+
+type SSHKeyCreateRequest struct {
+	Project   string `json:"project,omitempty"`
+	Name      string `json:"name"`
+	PublicKey string `json:"public_key"`
+}
+
+type SSHKeyUpdateRequest struct {
+	Project   string `json:"project,omitempty"`
+	Name      string `json:"name,omitempty"`
+	PublicKey string `json:"public_key,omitempty"`
+}
+
+type SecurityGroupCreateRequest struct {
+	Project string                     `json:"project,omitempty"`
+	Name    string                     `json:"name"`
+	Rules   []SecurityGroupRuleRequest `json:"rules"`
+}
+
+type SecurityGroupRuleRequest struct {
+	Protocol       string `json:"protocol,omitempty"`
+	PortRangeMin   int    `json:"port_range_min,omitempty"`
+	PortRangeMax   int    `json:"port_range_max,omitempty"`
+	Direction      string `json:"direction,omitempty"`
+	RemoteIpPrefix string `json:"remote_ip_prefix,omitempty"`
+	RemoteGroupId  string `json:"remote_group_id,omitempty"`
+	EtherType      string `json:"ether_type,omitempty"`
+}
+
+type SecurityGroupRuleResponse struct {
+	ID string `json:"id"`
+	SecurityGroupRuleRequest
+}
+
+// ServerAddress is an address of a server on one of its networks.
+type ServerAddress struct {
+	Address string `json:"address"`
+	// "fixed" for the address on the network, "floating" for a public address
+	// mapped to it.
+	Type string `json:"type"`
+	// 4 or 6.
+	Version int `json:"version,omitempty"`
+}
+
+type ServerCreateRequest struct {
+	Project             string               `json:"project,omitempty"`
+	Name                string               `json:"name"`
+	Flavor              string               `json:"flavor,omitempty"`
+	Image               string               `json:"image,omitempty"`
+	KeyName             string               `json:"key_name"`
+	SecurityGroups      []string             `json:"security_groups,omitempty"`
+	RequestFloatingIP   bool                 `json:"request_floating_ip"`
+	CloudInit           string               `json:"cloud_init,omitempty"`
+	Networks            []string             `json:"networks,omitempty"`
+	SubNetworks         []string             `json:"sub_networks,omitempty"`
+	Volumes             []string             `json:"volumes,omitempty"`
+	Security            *ServerSecurity      `json:"security,omitempty"`
+	SecretRefs          []string             `json:"secret_refs,omitempty"`
+	SchedulerHints      map[string]string    `json:"scheduler_hints,omitempty"`
+	NetworkQoS          []NetworkQoS         `json:"network_qos,omitempty"`
+	AdminUsername       string               `json:"admin_username,omitempty"`
+	AllowedAddressPairs []AllowedAddressPair `json:"allowed_address_pairs,omitempty"`
+	// Sent instead of cloud_init to deliver it encrypted with the project's
+	// public key.
+	EncryptedCloudInit *EncryptedUserData `json:"encrypted_cloud_init,omitempty"`
+	// Base64-encoded user data, sent instead of cloud_init for binary or
+	// gzip-compressed payloads.
+	UserData string `json:"user_data,omitempty"`
+	// Placement policy the server is scheduled under.
+	PlacementPolicy string `json:"placement_policy,omitempty"`
+	// Free-form key/value labels, e.g. role = "web".
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// ServerEffectiveConfigResponse is the configuration the platform applied to
+// a server, with every default resolved.
+type ServerEffectiveConfigResponse struct {
+	Flavor EffectiveFlavor `json:"flavor"`
+	Image  EffectiveImage  `json:"image"`
+	// Includes the groups the platform added by default.
+	SecurityGroups   []string `json:"security_groups"`
+	Networks         []string `json:"networks"`
+	AvailabilityZone string   `json:"availability_zone"`
+	KeyName          string   `json:"key_name"`
+	// When the configuration was last applied, in RFC 3339 format.
+	AppliedAt string `json:"applied_at"`
+}
+
+// ServerEvent is an entry in a server's lifecycle log, e.g. a scheduling
+// failure.
+type ServerEvent struct {
+	Time    string `json:"time"`
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+type ServerItem struct {
+	IP       string `json:"ip"`
+	Port     int    `json:"port"`
+	Endpoint string `json:"endpoint"`
+}
+
+type ServerMetricsResponse struct {
+	CPUPercent           float64 `json:"cpu_percent"`
+	MemoryPercent        float64 `json:"memory_percent"`
+	DiskReadBytesPerSec  float64 `json:"disk_read_bytes_per_sec"`
+	DiskWriteBytesPerSec float64 `json:"disk_write_bytes_per_sec"`
+	NetworkRxBytesPerSec float64 `json:"network_rx_bytes_per_sec"`
+	NetworkTxBytesPerSec float64 `json:"network_tx_bytes_per_sec"`
+	SampleCount          int     `json:"sample_count"`
+}
+
+// ServerPasswordResponse holds the administrator password generated for a
+// Windows server on first boot.
+type ServerPasswordResponse struct {
+	Password string `json:"password"`
+}
+
+type ServerProperties struct {
+	IPAddresses     []string          `json:"ip_addresses"`
+	RequestFloating bool              `json:"request_floating_ip"`
+	PowerState      string            `json:"power_state"`
+	TaskState       string            `json:"task_state"`
+	Flavor          string            `json:"flavor"`
+	Image           string            `json:"image"`
+	KeyName         string            `json:"key_name"`
+	SecurityGroups  []string          `json:"security_groups"`
+	Networks        []string          `json:"networks"`
+	SubNetworks     []string          `json:"sub_networks"`
+	Volumes         []string          `json:"volumes"`
+	SecretRefs      []string          `json:"secret_refs"`
+	SchedulerHints  map[string]string `json:"scheduler_hints"`
+	NetworkQoS      []NetworkQoS      `json:"network_qos"`
+	AdminUsername   string            `json:"admin_username"`
+	Security        *ServerSecurity   `json:"security"`
+	// Absent from API versions without allowed address pairs.
+	AllowedAddressPairs []AllowedAddressPair `json:"allowed_address_pairs,omitempty"`
+	Metadata            map[string]string    `json:"metadata,omitempty"`
+	// Addresses by network name. Absent from API versions that only report
+	// ip_addresses.
+	Addresses map[string][]ServerAddress `json:"addresses,omitempty"`
+}
+
+// ServerRebuildRequest reinstalls a server from another image, keeping its
+// name, addresses and volumes.
+type ServerRebuildRequest struct {
+	Image string `json:"image"`
+}
+
+type ServerSecurity struct {
+	EncryptedLocalDisks bool `json:"encrypted_local_disks"`
+	ConfidentialVM      bool `json:"confidential_vm"`
+}
+
+type ServerUpdateRequest struct {
+	Name                string                `json:"name"`
+	Flavor              *string               `json:"flavor,omitempty"`
+	Image               *string               `json:"image,omitempty"`
+	SecurityGroups      *[]string             `json:"security_groups,omitempty"`
+	RequestFloatingIP   *bool                 `json:"request_floating_ip,omitempty"`
+	Networks            *[]string             `json:"networks,omitempty"`
+	SubNetworks         *[]string             `json:"subnetworks,omitempty"`
+	Volumes             *[]string             `json:"volumes,omitempty"`
+	SecretRefs          *[]string             `json:"secret_refs,omitempty"`
+	NetworkQoS          *[]NetworkQoS         `json:"network_qos,omitempty"`
+	AllowedAddressPairs *[]AllowedAddressPair `json:"allowed_address_pairs,omitempty"`
+	Metadata            *map[string]string    `json:"metadata,omitempty"`
+}
+
+type SubnetCreateRequest struct {
+	Name         string      `json:"name"`
+	CIDR         string      `json:"cidr"`
+	Gateway      string      `json:"gateway,omitempty"`
+	StaticRoutes []RouteRule `json:"static_routes,omitempty"`
+}
+
+type SubnetResponse struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	CIDR    string `json:"cidr"`
+	Gateway string `json:"gateway"`
+}
+
+type TokenRefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type TokenRefreshResponse struct {
+	AccessToken string `json:"access_token"`
+	// Set when the API rotates the refresh token along with the access token.
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+// VersionResponse describes the API deployment. Capabilities name optional
+// features, e.g. "loadbalancer_allowed_cidrs".
+type VersionResponse struct {
+	Version      string   `json:"version"`
+	Capabilities []string `json:"capabilities"`
+}
+
+type VolumeCreateRequest struct {
+	Project string `json:"project,omitempty"`
+	Name    string `json:"name"`
+	Storage int    `json:"storage"`
+}
+
+type VolumeUpdateRequest struct {
+	Project string `json:"project,omitempty"`
+	Storage int    `json:"storage"`
+}
+
+type WhoamiResponse struct {
+	AccountID      string   `json:"account_id"`
+	Username       string   `json:"username"`
+	Email          string   `json:"email"`
+	Scopes         []string `json:"scopes"`
+	DefaultProject string   `json:"default_project"`
+	ExpiresAt      string   `json:"expires_at"`
+}
@@ -0,0 +1,36 @@
+package faxter
+
+import "context"
+
+// CreateNetwork creates a network and its subnets.
+func (c *Client) CreateNetwork(ctx context.Context, req *NetworkCreateRequest) (*ResourceResponse, error) {
+	var network ResourceResponse
+	if err := c.Do(ctx, "POST", "/networks/", req, &network); err != nil {
+		return nil, err
+	}
+	return &network, nil
+}
+
+// GetNetwork returns the named network.
+func (c *Client) GetNetwork(ctx context.Context, project, name string) (*NetworkResponse, error) {
+	var network NetworkResponse
+	if err := c.Do(ctx, "GET", ObjectPath("networks", project, name), nil, &network); err != nil {
+		return nil, err
+	}
+	return &network, nil
+}
+
+// ListNetworks returns every network in a project.
+func (c *Client) ListNetworks(ctx context.Context, project string) ([]NetworkResponse, error) {
+	return List[NetworkResponse](ctx, c, collectionPath("networks", project))
+}
+
+// UpdateNetwork replaces a network's definition; req.Name may rename it.
+func (c *Client) UpdateNetwork(ctx context.Context, project, name string, req *NetworkCreateRequest) error {
+	return c.Do(ctx, "PUT", ObjectPath("networks", project, name), req, nil)
+}
+
+// DeleteNetwork deletes the named network.
+func (c *Client) DeleteNetwork(ctx context.Context, project, name string) error {
+	return c.Do(ctx, "DELETE", ObjectPath("networks", project, name), nil, nil)
+}
@@ -0,0 +1,92 @@
+package faxter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DefaultPageSize is the number of items requested per page when
+// Client.PageSize is zero.
+const DefaultPageSize = 100
+
+// page is the envelope of a paginated list. Cursor-paginated endpoints set
+// NextCursor until the last page; page-numbered ones report the Total number
+// of items instead.
+type page struct {
+	Items      json.RawMessage `json:"items"`
+	NextCursor string          `json:"next_cursor"`
+	Total      int             `json:"total"`
+}
+
+// List returns every item of the list endpoint at path, following pages
+// until the set is complete. It understands cursor pagination (limit and
+// cursor parameters, next_cursor in the response), page numbers (limit and
+// page parameters, total in the response) and endpoints that return a plain
+// JSON array without paginating.
+func List[T any](ctx context.Context, c *Client, path string) ([]T, error) {
+	limit := c.PageSize
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+
+	var all []T
+	params := url.Values{"limit": {strconv.Itoa(limit)}}
+	seen := map[string]bool{}
+	for number := 1; ; number++ {
+		var raw json.RawMessage
+		if err := c.Do(ctx, "GET", withQuery(path, params), nil, &raw); err != nil {
+			return nil, err
+		}
+
+		if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '[' {
+			var items []T
+			if err := c.Unmarshal(trimmed, &items); err != nil {
+				return nil, err
+			}
+			return append(all, items...), nil
+		}
+
+		var p page
+		if err := json.Unmarshal(raw, &p); err != nil {
+			return nil, err
+		}
+		var items []T
+		if len(p.Items) > 0 {
+			if err := c.Unmarshal(p.Items, &items); err != nil {
+				return nil, err
+			}
+		}
+		all = append(all, items...)
+
+		switch {
+		case p.NextCursor != "":
+			if seen[p.NextCursor] {
+				return nil, fmt.Errorf("listing %s: the API returned cursor %q twice", path, p.NextCursor)
+			}
+			seen[p.NextCursor] = true
+			params.Set("cursor", p.NextCursor)
+		case len(items) > 0 && len(all) < p.Total:
+			params.Set("page", strconv.Itoa(number+1))
+		default:
+			return all, nil
+		}
+	}
+}
+
+// withQuery returns path with params added to any query it already has.
+func withQuery(path string, params url.Values) string {
+	base, rawQuery, _ := strings.Cut(path, "?")
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		query = url.Values{}
+	}
+	for k, v := range params {
+		query[k] = v
+	}
+	return base + "?" + query.Encode()
+}
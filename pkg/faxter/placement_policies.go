@@ -0,0 +1,30 @@
+package faxter
+
+import "context"
+
+// CreatePlacementPolicy creates a placement policy.
+func (c *Client) CreatePlacementPolicy(ctx context.Context, req *PlacementPolicyRequest) error {
+	return c.Do(ctx, "POST", "/placement_policies/", req, nil)
+}
+
+// GetPlacementPolicy returns the named placement policy.
+func (c *Client) GetPlacementPolicy(ctx context.Context, project, name string) (*PlacementPolicyResponse, error) {
+	var policy PlacementPolicyResponse
+	if err := c.Do(ctx, "GET", ObjectPath("placement_policies", project, name), nil, &policy); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// UpdatePlacementPolicy replaces a placement policy's rules. Servers already
+// placed under the policy stay where they are; the rules apply from their
+// next scheduling on.
+func (c *Client) UpdatePlacementPolicy(ctx context.Context, project, name string, req *PlacementPolicyRequest) error {
+	return c.Do(ctx, "PUT", ObjectPath("placement_policies", project, name), req, nil)
+}
+
+// DeletePlacementPolicy deletes the named placement policy. The API refuses
+// while servers are placed under it.
+func (c *Client) DeletePlacementPolicy(ctx context.Context, project, name string) error {
+	return c.Do(ctx, "DELETE", ObjectPath("placement_policies", project, name), nil, nil)
+}
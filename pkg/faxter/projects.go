@@ -0,0 +1,42 @@
+package faxter
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+func projectPath(name string) string {
+	return fmt.Sprintf("/projects/%s", url.PathEscape(name))
+}
+
+// CreateProject creates a project.
+func (c *Client) CreateProject(ctx context.Context, name string) error {
+	return c.Do(ctx, "POST", "/projects", &ProjectCreateRequest{Name: name}, nil)
+}
+
+// CheckCredentials makes the cheapest authenticated request the API offers,
+// listing projects, to confirm the client's credentials are accepted.
+func (c *Client) CheckCredentials(ctx context.Context) error {
+	return c.Do(ctx, "GET", "/projects", nil, nil)
+}
+
+// ProjectExists reports whether the named project exists.
+func (c *Client) ProjectExists(ctx context.Context, name string) (bool, error) {
+	err := c.Do(ctx, "GET", projectPath(name), nil, nil)
+	if IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// RenameProject renames a project. Some API deployments accept the request
+// without renaming anything, so callers should confirm the new name exists.
+func (c *Client) RenameProject(ctx context.Context, name, newName string) error {
+	return c.Do(ctx, "PUT", projectPath(name), &ProjectCreateRequest{Name: newName}, nil)
+}
+
+// DeleteProject deletes a project.
+func (c *Client) DeleteProject(ctx context.Context, name string) error {
+	return c.Do(ctx, "DELETE", projectPath(name), nil, nil)
+}
@@ -0,0 +1,27 @@
+package faxter
+
+import "context"
+
+// CreateQoSPolicy creates a QoS policy.
+func (c *Client) CreateQoSPolicy(ctx context.Context, req *QoSPolicyRequest) error {
+	return c.Do(ctx, "POST", "/qos_policies/", req, nil)
+}
+
+// GetQoSPolicy returns the named QoS policy.
+func (c *Client) GetQoSPolicy(ctx context.Context, project, name string) (*QoSPolicyResponse, error) {
+	var policy QoSPolicyResponse
+	if err := c.Do(ctx, "GET", ObjectPath("qos_policies", project, name), nil, &policy); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// UpdateQoSPolicy replaces a QoS policy's limits.
+func (c *Client) UpdateQoSPolicy(ctx context.Context, project, name string, req *QoSPolicyRequest) error {
+	return c.Do(ctx, "PUT", ObjectPath("qos_policies", project, name), req, nil)
+}
+
+// DeleteQoSPolicy deletes the named QoS policy.
+func (c *Client) DeleteQoSPolicy(ctx context.Context, project, name string) error {
+	return c.Do(ctx, "DELETE", ObjectPath("qos_policies", project, name), nil, nil)
+}
@@ -0,0 +1,27 @@
+package faxter
+
+import "context"
+
+// CreateQuotaRequest files a quota increase request for review.
+func (c *Client) CreateQuotaRequest(ctx context.Context, req *QuotaRequestRequest) (*QuotaRequestResponse, error) {
+	var quotaReq QuotaRequestResponse
+	if err := c.Do(ctx, "POST", "/quota_requests/", req, &quotaReq); err != nil {
+		return nil, err
+	}
+	return &quotaReq, nil
+}
+
+// GetQuotaRequest returns a quota request and its review status.
+func (c *Client) GetQuotaRequest(ctx context.Context, project, id string) (*QuotaRequestResponse, error) {
+	var quotaReq QuotaRequestResponse
+	if err := c.Do(ctx, "GET", ObjectPath("quota_requests", project, id), nil, &quotaReq); err != nil {
+		return nil, err
+	}
+	return &quotaReq, nil
+}
+
+// WithdrawQuotaRequest withdraws a pending quota request. The API refuses
+// with 409 Conflict once the request has been reviewed.
+func (c *Client) WithdrawQuotaRequest(ctx context.Context, project, id string) error {
+	return c.Do(ctx, "DELETE", ObjectPath("quota_requests", project, id), nil, nil)
+}
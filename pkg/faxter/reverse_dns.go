@@ -0,0 +1,27 @@
+package faxter
+
+import "context"
+
+// CreateReverseDNS sets the PTR record of a floating IP.
+func (c *Client) CreateReverseDNS(ctx context.Context, req *ReverseDNSRequest) error {
+	return c.Do(ctx, "POST", "/reverse_dns/", req, nil)
+}
+
+// GetReverseDNS returns the PTR record of a floating IP.
+func (c *Client) GetReverseDNS(ctx context.Context, project, floatingIP string) (*ReverseDNSResponse, error) {
+	var rdns ReverseDNSResponse
+	if err := c.Do(ctx, "GET", ObjectPath("reverse_dns", project, floatingIP), nil, &rdns); err != nil {
+		return nil, err
+	}
+	return &rdns, nil
+}
+
+// UpdateReverseDNS changes the PTR record of a floating IP.
+func (c *Client) UpdateReverseDNS(ctx context.Context, project, floatingIP string, req *ReverseDNSRequest) error {
+	return c.Do(ctx, "PUT", ObjectPath("reverse_dns", project, floatingIP), req, nil)
+}
+
+// DeleteReverseDNS removes the PTR record of a floating IP.
+func (c *Client) DeleteReverseDNS(ctx context.Context, project, floatingIP string) error {
+	return c.Do(ctx, "DELETE", ObjectPath("reverse_dns", project, floatingIP), nil, nil)
+}
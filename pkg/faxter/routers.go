@@ -0,0 +1,31 @@
+package faxter
+
+import "context"
+
+// CreateRouter creates a router attached to the given subnets.
+func (c *Client) CreateRouter(ctx context.Context, req *RouterCreateRequest) (*ResourceResponse, error) {
+	var router ResourceResponse
+	if err := c.Do(ctx, "POST", "/routers/", req, &router); err != nil {
+		return nil, err
+	}
+	return &router, nil
+}
+
+// GetRouter returns the named router.
+func (c *Client) GetRouter(ctx context.Context, project, name string) (*ResourceResponse, error) {
+	var router ResourceResponse
+	if err := c.Do(ctx, "GET", ObjectPath("routers", project, name), nil, &router); err != nil {
+		return nil, err
+	}
+	return &router, nil
+}
+
+// UpdateRouter replaces a router's definition; req.Name may rename it.
+func (c *Client) UpdateRouter(ctx context.Context, project, name string, req *RouterCreateRequest) error {
+	return c.Do(ctx, "PUT", ObjectPath("routers", project, name), req, nil)
+}
+
+// DeleteRouter deletes the named router.
+func (c *Client) DeleteRouter(ctx context.Context, project, name string) error {
+	return c.Do(ctx, "DELETE", ObjectPath("routers", project, name), nil, nil)
+}
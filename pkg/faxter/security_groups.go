@@ -0,0 +1,72 @@
+package faxter
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+func securityGroupRulesPath(project, securityGroup string) string {
+	return fmt.Sprintf("/security_groups/%s/rules?project_name=%s", url.PathEscape(securityGroup), url.QueryEscape(project))
+}
+
+func securityGroupRulePath(project, securityGroup, ruleID string) string {
+	return fmt.Sprintf("/security_groups/%s/rules/%s?project_name=%s", url.PathEscape(securityGroup), url.PathEscape(ruleID), url.QueryEscape(project))
+}
+
+// CreateSecurityGroup creates a security group with an initial rule set.
+func (c *Client) CreateSecurityGroup(ctx context.Context, req *SecurityGroupCreateRequest) (*ResourceResponse, error) {
+	var group ResourceResponse
+	if err := c.Do(ctx, "POST", "/security_groups/", req, &group); err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// GetSecurityGroup returns the named security group.
+func (c *Client) GetSecurityGroup(ctx context.Context, project, name string) (*ResourceResponse, error) {
+	var group ResourceResponse
+	if err := c.Do(ctx, "GET", ObjectPath("security_groups", project, name), nil, &group); err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// UpdateSecurityGroup replaces a security group's definition; req.Name may
+// rename it.
+func (c *Client) UpdateSecurityGroup(ctx context.Context, project, name string, req *SecurityGroupCreateRequest) error {
+	return c.Do(ctx, "PUT", ObjectPath("security_groups", project, name), req, nil)
+}
+
+// DeleteSecurityGroup deletes the named security group.
+func (c *Client) DeleteSecurityGroup(ctx context.Context, project, name string) error {
+	return c.Do(ctx, "DELETE", ObjectPath("security_groups", project, name), nil, nil)
+}
+
+// CreateSecurityGroupRule adds a rule to a security group.
+func (c *Client) CreateSecurityGroupRule(ctx context.Context, project, securityGroup string, req *SecurityGroupRuleRequest) (*SecurityGroupRuleResponse, error) {
+	var rule SecurityGroupRuleResponse
+	if err := c.Do(ctx, "POST", securityGroupRulesPath(project, securityGroup), req, &rule); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// GetSecurityGroupRule returns one rule of a security group.
+func (c *Client) GetSecurityGroupRule(ctx context.Context, project, securityGroup, ruleID string) (*SecurityGroupRuleResponse, error) {
+	var rule SecurityGroupRuleResponse
+	if err := c.Do(ctx, "GET", securityGroupRulePath(project, securityGroup, ruleID), nil, &rule); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// ListSecurityGroupRules returns every rule of a security group.
+func (c *Client) ListSecurityGroupRules(ctx context.Context, project, securityGroup string) ([]SecurityGroupRuleResponse, error) {
+	return List[SecurityGroupRuleResponse](ctx, c, securityGroupRulesPath(project, securityGroup))
+}
+
+// DeleteSecurityGroupRule removes a rule from a security group.
+func (c *Client) DeleteSecurityGroupRule(ctx context.Context, project, securityGroup, ruleID string) error {
+	return c.Do(ctx, "DELETE", securityGroupRulePath(project, securityGroup, ruleID), nil, nil)
+}
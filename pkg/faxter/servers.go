@@ -0,0 +1,130 @@
+package faxter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+func (r *ResourceResponse) UnmarshalJSON(data []byte) error {
+	type plain ResourceResponse
+	if err := json.Unmarshal(data, (*plain)(r)); err != nil {
+		return err
+	}
+	r.RawProperties = rawProperties(data)
+	return nil
+}
+
+// rawProperties returns the properties object of a JSON response body.
+func rawProperties(data []byte) json.RawMessage {
+	var raw struct {
+		Properties json.RawMessage `json:"properties"`
+	}
+	if json.Unmarshal(data, &raw) != nil {
+		return nil
+	}
+	return raw.Properties
+}
+
+// CreateServer requests a server. The API answers before the server is
+// online; poll GetServer until its status is "online".
+func (c *Client) CreateServer(ctx context.Context, req *ServerCreateRequest) ([]ResourceResponse, error) {
+	var servers []ResourceResponse
+	if err := c.Do(ctx, "POST", "/servers/", req, &servers); err != nil {
+		return nil, err
+	}
+	return servers, nil
+}
+
+// GetServer returns the named server.
+func (c *Client) GetServer(ctx context.Context, project, name string) (*ResourceResponse, error) {
+	var server ResourceResponse
+	if err := c.Do(ctx, "GET", ObjectPath("servers", project, name), nil, &server); err != nil {
+		return nil, err
+	}
+	return &server, nil
+}
+
+// ListServers returns every server in a project.
+func (c *Client) ListServers(ctx context.Context, project string) ([]ResourceResponse, error) {
+	return List[ResourceResponse](ctx, c, collectionPath("servers", project))
+}
+
+// UpdateServer changes the fields set in req.
+func (c *Client) UpdateServer(ctx context.Context, project, name string, req *ServerUpdateRequest) error {
+	return c.Do(ctx, "PUT", ObjectPath("servers", project, name), req, nil)
+}
+
+// DeleteServer deletes the named server.
+func (c *Client) DeleteServer(ctx context.Context, project, name string) error {
+	return c.Do(ctx, "DELETE", ObjectPath("servers", project, name), nil, nil)
+}
+
+// AttachVolume attaches a volume to a server. The API answers once the
+// attachment has started; poll GetVolume until its status is "in-use".
+func (c *Client) AttachVolume(ctx context.Context, project, server, volume string) error {
+	return c.Do(ctx, "POST", serverVolumePath(project, server, volume), nil, nil)
+}
+
+// DetachVolume detaches a volume from a server. The API answers once the
+// detachment has started; poll GetVolume until its status is "available".
+func (c *Client) DetachVolume(ctx context.Context, project, server, volume string) error {
+	return c.Do(ctx, "DELETE", serverVolumePath(project, server, volume), nil, nil)
+}
+
+// RebuildServer reinstalls a server from req.Image. The API answers once
+// the rebuild has started; poll GetServer until its status is "online".
+func (c *Client) RebuildServer(ctx context.Context, project, name string, req *ServerRebuildRequest) error {
+	path := fmt.Sprintf("/servers/%s/rebuild?project_name=%s", url.PathEscape(name), url.QueryEscape(project))
+	return c.Do(ctx, "POST", path, req, nil)
+}
+
+func serverVolumePath(project, server, volume string) string {
+	return fmt.Sprintf("/servers/%s/volumes/%s?project_name=%s", url.PathEscape(server), url.PathEscape(volume), url.QueryEscape(project))
+}
+
+// GetServerMetrics returns a server's utilisation aggregated with statistic
+// (e.g. "avg") over window (e.g. "1h").
+func (c *Client) GetServerMetrics(ctx context.Context, project, name, window, statistic string) (*ServerMetricsResponse, error) {
+	query := url.Values{}
+	query.Set("project_name", project)
+	query.Set("window", window)
+	query.Set("statistic", statistic)
+
+	var metrics ServerMetricsResponse
+	path := fmt.Sprintf("/servers/%s/metrics?%s", url.PathEscape(name), query.Encode())
+	if err := c.Do(ctx, "GET", path, nil, &metrics); err != nil {
+		return nil, err
+	}
+	return &metrics, nil
+}
+
+// GetServerEffectiveConfig returns the configuration the platform applied
+// to a server, with defaults such as added security groups resolved.
+func (c *Client) GetServerEffectiveConfig(ctx context.Context, project, name string) (*ServerEffectiveConfigResponse, error) {
+	var config ServerEffectiveConfigResponse
+	path := fmt.Sprintf("/servers/%s/effective_config?project_name=%s", url.PathEscape(name), url.QueryEscape(project))
+	if err := c.Do(ctx, "GET", path, nil, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// GetServerPassword returns the administrator password of a Windows server.
+// Until the guest has finished its first boot the API answers 404 or an
+// empty password.
+func (c *Client) GetServerPassword(ctx context.Context, project, name string) (*ServerPasswordResponse, error) {
+	var password ServerPasswordResponse
+	path := fmt.Sprintf("/servers/%s/password?project_name=%s", url.PathEscape(name), url.QueryEscape(project))
+	if err := c.Do(ctx, "GET", path, nil, &password); err != nil {
+		return nil, err
+	}
+	return &password, nil
+}
+
+// ListServerEvents returns a server's lifecycle events, oldest first.
+func (c *Client) ListServerEvents(ctx context.Context, project, name string) ([]ServerEvent, error) {
+	path := fmt.Sprintf("/servers/%s/events?project_name=%s", url.PathEscape(name), url.QueryEscape(project))
+	return List[ServerEvent](ctx, c, path)
+}
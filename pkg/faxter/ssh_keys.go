@@ -0,0 +1,39 @@
+package faxter
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+func sshKeyPath(name string) string {
+	return fmt.Sprintf("/ssh_keys/%s", url.PathEscape(name))
+}
+
+// CreateSSHKey uploads a public key.
+func (c *Client) CreateSSHKey(ctx context.Context, req *SSHKeyCreateRequest) (*ResourceResponse, error) {
+	var key ResourceResponse
+	if err := c.Do(ctx, "POST", "/ssh_keys/", req, &key); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// GetSSHKey returns the named key.
+func (c *Client) GetSSHKey(ctx context.Context, name string) (*ResourceResponse, error) {
+	var key ResourceResponse
+	if err := c.Do(ctx, "GET", sshKeyPath(name), nil, &key); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// UpdateSSHKey changes the fields set in req.
+func (c *Client) UpdateSSHKey(ctx context.Context, name string, req *SSHKeyUpdateRequest) error {
+	return c.Do(ctx, "PUT", sshKeyPath(name), req, nil)
+}
+
+// DeleteSSHKey deletes the named key.
+func (c *Client) DeleteSSHKey(ctx context.Context, name string) error {
+	return c.Do(ctx, "DELETE", sshKeyPath(name), nil, nil)
+}
@@ -0,0 +1,136 @@
+package faxter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+var rawMessageType = reflect.TypeOf(json.RawMessage(nil))
+
+// DecodeResponse reads the JSON body of resp into out like the package's
+// DecodeResponse and, when c.StrictDecoding is set, checks it against out's
+// fields.
+func (c *Client) DecodeResponse(resp *http.Response, out interface{}) error {
+	return decodeResponse(resp, out, c.StrictDecoding)
+}
+
+// Unmarshal decodes data into out like json.Unmarshal and, when
+// c.StrictDecoding is set, checks it against out's fields.
+func (c *Client) Unmarshal(data []byte, out interface{}) error {
+	if err := json.Unmarshal(data, out); err != nil {
+		return err
+	}
+	if c.StrictDecoding {
+		return checkFields(data, reflect.TypeOf(out), "")
+	}
+	return nil
+}
+
+// checkFields reports the first difference between the JSON in data and the
+// fields of type t: an object key that no field takes, or a field that is
+// neither present nor marked omitempty. The models mark exactly the fields
+// the API may leave out as omitempty, so either one means that the API and
+// the client disagree about the schema. Values that fail to decode at all
+// are left for the decoder to report.
+func checkFields(data []byte, t reflect.Type, path string) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return checkFields(data, t.Elem(), path)
+
+	case reflect.Slice, reflect.Array:
+		if t == rawMessageType || t.Elem().Kind() == reflect.Uint8 {
+			return nil
+		}
+		var items []json.RawMessage
+		if json.Unmarshal(data, &items) != nil {
+			return nil
+		}
+		for i, item := range items {
+			if err := checkFields(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		var items map[string]json.RawMessage
+		if json.Unmarshal(data, &items) != nil {
+			return nil
+		}
+		for _, k := range sortedKeys(items) {
+			if err := checkFields(items[k], t.Elem(), fmt.Sprintf("%s[%q]", path, k)); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Struct:
+		var fields map[string]json.RawMessage
+		if json.Unmarshal(data, &fields) != nil {
+			return nil
+		}
+		known := map[string]bool{}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name, options, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			known[name] = true
+
+			value, ok := fields[name]
+			if !ok {
+				if !strings.Contains(options, "omitempty") {
+					return fmt.Errorf("the API left out field %q %s", name, describePath(path))
+				}
+				continue
+			}
+			if err := checkFields(value, f.Type, joinPath(path, name)); err != nil {
+				return err
+			}
+		}
+		for _, name := range sortedKeys(fields) {
+			if !known[name] {
+				return fmt.Errorf("the API sent unknown field %q %s", name, describePath(path))
+			}
+		}
+	}
+	return nil
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func describePath(path string) string {
+	if path == "" {
+		return "in the response"
+	}
+	return "in " + path
+}
+
+func sortedKeys(m map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,28 @@
+package faxter
+
+import (
+	"net/http"
+	"time"
+)
+
+const (
+	// DefaultMaxIdleConnsPerHost is the number of idle connections to the
+	// API kept open for reuse. Go's default of 2 is too few for Terraform's
+	// parallel walks, which then open and tear down connections constantly.
+	DefaultMaxIdleConnsPerHost = 32
+
+	// DefaultIdleConnTimeout is how long an idle connection is kept open.
+	DefaultIdleConnTimeout = 90 * time.Second
+)
+
+// NewTransport returns a transport for the API that negotiates HTTP/2 and
+// keeps a pool of DefaultMaxIdleConnsPerHost idle connections per host, so
+// that a run sending hundreds of requests reuses its connections.
+func NewTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ForceAttemptHTTP2 = true
+	transport.MaxIdleConns = 0
+	transport.MaxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+	transport.IdleConnTimeout = DefaultIdleConnTimeout
+	return transport
+}
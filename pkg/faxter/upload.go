@@ -0,0 +1,176 @@
+package faxter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"sort"
+)
+
+// Upload is a file sent to the API as a multipart/form-data request.
+type Upload struct {
+	// File is read from for the upload; an *os.File will do. Reading at
+	// offsets lets the body be reopened when a request is retried, so it is
+	// never held in memory.
+	File io.ReaderAt
+
+	// Size is the number of bytes of File to send.
+	Size int64
+
+	// FileName is reported as the filename of the file part.
+	FileName string
+
+	// Field names the file part. It defaults to "file".
+	Field string
+
+	// Fields are sent as form fields ahead of the file.
+	Fields map[string]string
+
+	// ChunkSize, when positive, splits the file into parts of at most this
+	// many bytes, sent one request each, so that a failed upload can be
+	// resumed rather than started over.
+	ChunkSize int64
+
+	// Offset is the number of bytes the API already has. A chunked upload
+	// starts from there.
+	Offset int64
+
+	// Progress, when set, is called as the file is read with the number of
+	// bytes read so far, including Offset, and Size. A request that is
+	// retried or signed reads its part again.
+	Progress func(sent, total int64)
+}
+
+// UploadError is returned when a chunked upload fails part way. Offset is
+// the number of bytes the API acknowledged: setting Upload.Offset to it and
+// calling Client.Upload again sends the rest.
+type UploadError struct {
+	Offset int64
+	Err    error
+}
+
+func (e *UploadError) Error() string {
+	return fmt.Sprintf("upload failed after %d bytes: %s", e.Offset, e.Err)
+}
+
+func (e *UploadError) Unwrap() error {
+	return e.Err
+}
+
+// Upload sends u to path in POST requests and decodes the JSON response to
+// the last one into out. The body is streamed with chunked transfer encoding
+// rather than buffered. In a chunked upload every request carries a
+// Content-Range header, e.g. "bytes 0-1048575/5242880", locating its part.
+func (c *Client) Upload(ctx context.Context, path string, u *Upload, out interface{}) error {
+	boundary := multipart.NewWriter(io.Discard).Boundary()
+
+	if u.ChunkSize <= 0 {
+		return c.uploadPart(ctx, path, u, boundary, 0, u.Size, false, out)
+	}
+	for start := u.Offset; start < u.Size; start += u.ChunkSize {
+		end := min(start+u.ChunkSize, u.Size)
+		var partOut interface{}
+		if end == u.Size {
+			partOut = out
+		}
+		if err := c.uploadPart(ctx, path, u, boundary, start, end, true, partOut); err != nil {
+			return &UploadError{Offset: start, Err: err}
+		}
+	}
+	return nil
+}
+
+// uploadPart sends bytes [start, end) of u in one request.
+func (c *Client) uploadPart(ctx context.Context, path string, u *Upload, boundary string, start, end int64, ranged bool, out interface{}) error {
+	req, err := c.NewRequest("POST", path)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "multipart/form-data; boundary="+boundary)
+	if ranged {
+		req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end-1, u.Size))
+	}
+	req.GetBody = func() (io.ReadCloser, error) {
+		return u.stream(boundary, start, end), nil
+	}
+	req.Body, _ = req.GetBody()
+	// Unknown, so that the body is sent with chunked transfer encoding.
+	req.ContentLength = -1
+
+	resp, err := c.HTTPClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return NewError(resp)
+	}
+
+	if out == nil {
+		return nil
+	}
+	return c.DecodeResponse(resp, out)
+}
+
+// stream returns the multipart body carrying bytes [start, end) of the file,
+// written as it is read.
+func (u *Upload) stream(boundary string, start, end int64) io.ReadCloser {
+	r, w := io.Pipe()
+	go func() {
+		mw := multipart.NewWriter(w)
+		err := mw.SetBoundary(boundary)
+
+		keys := make([]string, 0, len(u.Fields))
+		for k := range u.Fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if err == nil {
+				err = mw.WriteField(k, u.Fields[k])
+			}
+		}
+
+		field := u.Field
+		if field == "" {
+			field = "file"
+		}
+		var part io.Writer
+		if err == nil {
+			part, err = mw.CreateFormFile(field, u.FileName)
+		}
+		if err == nil {
+			_, err = io.Copy(part, &progressReader{
+				r:        io.NewSectionReader(u.File, start, end-start),
+				sent:     start,
+				total:    u.Size,
+				progress: u.Progress,
+			})
+		}
+		if err == nil {
+			err = mw.Close()
+		}
+		w.CloseWithError(err)
+	}()
+	return r
+}
+
+// progressReader reports the bytes read through it.
+type progressReader struct {
+	r        io.Reader
+	sent     int64
+	total    int64
+	progress func(sent, total int64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 && p.progress != nil {
+		p.sent += int64(n)
+		p.progress(p.sent, p.total)
+	}
+	return n, err
+}
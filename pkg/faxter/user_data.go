@@ -0,0 +1,76 @@
+package faxter
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+)
+
+// UserDataAlgorithm is the only encryption the API accepts for user data.
+const UserDataAlgorithm = "RSA-OAEP-256+A256GCM"
+
+// GetProjectPublicKey returns the key to encrypt a project's user data with.
+func (c *Client) GetProjectPublicKey(ctx context.Context, project string) (*ProjectPublicKeyResponse, error) {
+	var key ProjectPublicKeyResponse
+	if err := c.Do(ctx, "GET", projectPath(project)+"/public_key", nil, &key); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// EncryptUserData seals plaintext for key, so that only the API can read it.
+// Send the result as ServerCreateRequest.EncryptedCloudInit in place of
+// CloudInit.
+func EncryptUserData(key *ProjectPublicKeyResponse, plaintext []byte) (*EncryptedUserData, error) {
+	if key.Algorithm != UserDataAlgorithm {
+		return nil, fmt.Errorf("unsupported user data encryption %q", key.Algorithm)
+	}
+	block, _ := pem.Decode([]byte(key.PublicKey))
+	if block == nil {
+		return nil, fmt.Errorf("public key %s is not PEM-encoded", key.KeyID)
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("public key %s: %w", key.KeyID, err)
+	}
+	rsaKey, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key %s is not an RSA key", key.KeyID)
+	}
+
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+	aesBlock, err := aes.NewCipher(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(aesBlock)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	encryptedKey, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, rsaKey, dataKey, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt with public key %s: %w", key.KeyID, err)
+	}
+
+	return &EncryptedUserData{
+		KeyID:        key.KeyID,
+		Algorithm:    UserDataAlgorithm,
+		EncryptedKey: base64.StdEncoding.EncodeToString(encryptedKey),
+		Nonce:        base64.StdEncoding.EncodeToString(nonce),
+		Ciphertext:   base64.StdEncoding.EncodeToString(gcm.Seal(nil, nonce, plaintext, nil)),
+	}, nil
+}
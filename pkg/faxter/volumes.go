@@ -0,0 +1,31 @@
+package faxter
+
+import "context"
+
+// CreateVolume creates a volume of req.Storage GB.
+func (c *Client) CreateVolume(ctx context.Context, req *VolumeCreateRequest) (*ResourceResponse, error) {
+	var volume ResourceResponse
+	if err := c.Do(ctx, "POST", "/volumes/", req, &volume); err != nil {
+		return nil, err
+	}
+	return &volume, nil
+}
+
+// GetVolume returns the named volume.
+func (c *Client) GetVolume(ctx context.Context, project, name string) (*ResourceResponse, error) {
+	var volume ResourceResponse
+	if err := c.Do(ctx, "GET", ObjectPath("volumes", project, name), nil, &volume); err != nil {
+		return nil, err
+	}
+	return &volume, nil
+}
+
+// UpdateVolume resizes a volume.
+func (c *Client) UpdateVolume(ctx context.Context, project, name string, req *VolumeUpdateRequest) error {
+	return c.Do(ctx, "PUT", ObjectPath("volumes", project, name), req, nil)
+}
+
+// DeleteVolume deletes the named volume.
+func (c *Client) DeleteVolume(ctx context.Context, project, name string) error {
+	return c.Do(ctx, "DELETE", ObjectPath("volumes", project, name), nil, nil)
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// defaultPollSchedule is the progressive interval between status polls of a
+// resource being waited on. Long-running operations such as bare-metal builds
+// back off towards the last interval instead of polling every 10 seconds.
+var defaultPollSchedule = []time.Duration{10 * time.Second, 30 * time.Second, 60 * time.Second}
+
+// defaultErrorRetries is how many consecutive polls a new server may report
+// "error" before its creation fails.
+const defaultErrorRetries = 3
+
+// pollsPerStep is how many polls are made at each interval of a schedule
+// before moving on to the next one. The last interval repeats until the wait
+// ends.
+const pollsPerStep = 6
+
+// pollDelay returns how long to wait before the given (zero-based) poll.
+func pollDelay(schedule []time.Duration, attempt int) time.Duration {
+	step := attempt / pollsPerStep
+	if step >= len(schedule) {
+		step = len(schedule) - 1
+	}
+	return schedule[step]
+}
+
+// statusPoller serves status lookups for servers that are being waited on.
+// Rather than every waiting resource issuing its own GET, lookups queue up
+// and are answered together with a single list call per project, so a large
+// apply doesn't multiply the request rate. Lookups are only batched with
+// others made with the same request headers, so that a server created with
+// request_headers is polled with them too. The poller wakes once per interval
+// and answers the lookups that have come due, so a lookup's delay is rounded
+// up to a multiple of the interval.
+type statusPoller struct {
+	c        *Client
+	interval time.Duration
+
+	mu      sync.Mutex
+	waiters map[string][]statusWaiter // keyed by pollKey
+	running bool
+}
+
+type statusWaiter struct {
+	ctx     context.Context
+	c       *Client
+	project string
+	name    string
+	due     time.Time
+	result  chan statusResult
+}
+
+type statusResult struct {
+	server *faxter.ResourceResponse
+	err    error
+}
+
+func newStatusPoller(c *Client, interval time.Duration) *statusPoller {
+	return &statusPoller{
+		c:        c,
+		interval: interval,
+		waiters:  make(map[string][]statusWaiter),
+	}
+}
+
+// status blocks until the first poll round at least delay from now and
+// returns the server as seen in it, polled through c. A server missing from
+// the listing is reported as an error wrapping errNotFound.
+func (p *statusPoller) status(ctx context.Context, c *Client, project, name string, delay time.Duration) (*faxter.ResourceResponse, error) {
+	// Buffered so the poller never blocks on a waiter that has given up.
+	result := make(chan statusResult, 1)
+	waiter := statusWaiter{ctx: ctx, c: c, project: project, name: name, due: time.Now().Add(delay), result: result}
+
+	key := pollKey(project, c.rest.Headers)
+	p.mu.Lock()
+	p.waiters[key] = append(p.waiters[key], waiter)
+	if !p.running {
+		p.running = true
+		go p.run()
+	}
+	p.mu.Unlock()
+
+	select {
+	case r := <-result:
+		return r.server, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// run answers queued lookups that have come due once per interval and exits
+// when a round finds nobody waiting. When the provider is asked to stop, the
+// round runs at once so that it finds the cancelled waiters gone.
+func (p *statusPoller) run() {
+	for {
+		timer := time.NewTimer(p.interval)
+		select {
+		case <-timer.C:
+		case <-p.c.stop.Done():
+			timer.Stop()
+		}
+
+		p.mu.Lock()
+		if len(p.waiters) == 0 {
+			p.running = false
+			p.mu.Unlock()
+			return
+		}
+		now := time.Now()
+		batch := make(map[string][]statusWaiter)
+		for key, waiters := range p.waiters {
+			var pending []statusWaiter
+			for _, w := range waiters {
+				// A waiter whose apply was cancelled has stopped
+				// listening; don't poll on its behalf.
+				if w.ctx.Err() != nil {
+					continue
+				}
+				if w.due.After(now) {
+					pending = append(pending, w)
+				} else {
+					batch[key] = append(batch[key], w)
+				}
+			}
+			if len(pending) == 0 {
+				delete(p.waiters, key)
+			} else {
+				p.waiters[key] = pending
+			}
+		}
+		p.mu.Unlock()
+
+		for _, waiters := range batch {
+			p.poll(waiters)
+		}
+	}
+}
+
+// poll answers waiters sharing a pollKey, through the client of the first.
+func (p *statusPoller) poll(waiters []statusWaiter) {
+	// The poll serves many waiters, so it isn't tied to any one of their
+	// contexts, only to the provider's.
+	ctx, cancel := context.WithTimeout(p.c.stop, time.Minute)
+	defer cancel()
+
+	c, project := waiters[0].c, waiters[0].project
+	servers, err := listServers(ctx, c, project)
+	if err != nil {
+		tflog.Debug(ctx, "Listing servers failed, polling individually", map[string]interface{}{
+			"project": project,
+			"error":   err.Error(),
+		})
+		for _, w := range waiters {
+			server, err := getServerStatus(ctx, c, project, w.name)
+			w.result <- statusResult{server: server, err: err}
+		}
+		return
+	}
+
+	byName := make(map[string]*faxter.ResourceResponse, len(servers))
+	for i := range servers {
+		byName[servers[i].Name] = &servers[i]
+	}
+
+	for _, w := range waiters {
+		if server, ok := byName[w.name]; ok {
+			w.result <- statusResult{server: server}
+		} else {
+			w.result <- statusResult{err: fmt.Errorf("server '%s' %w", w.name, errNotFound)}
+		}
+	}
+}
+
+// pollKey groups lookups that can be answered by the same listing: those
+// for one project made with the same request headers.
+func pollKey(project string, headers http.Header) string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString(project)
+	for _, name := range names {
+		fmt.Fprintf(&b, "\n%s: %s", name, strings.Join(headers[name], ", "))
+	}
+	return b.String()
+}
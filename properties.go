@@ -0,0 +1,32 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/structure"
+)
+
+// propertiesJSONSchema is the properties_json attribute of resources whose
+// API responses carry a properties object.
+func propertiesJSONSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The properties object returned by the API, as JSON, for fields the provider does not model yet. Decode it with jsondecode(); its shape follows the API and may change between API versions.",
+	}
+}
+
+// setPropertiesJSON records the raw properties of an API response. A
+// response without properties leaves the attribute empty.
+func setPropertiesJSON(d *schema.ResourceData, raw json.RawMessage) error {
+	if len(raw) == 0 || string(raw) == "null" {
+		return d.Set("properties_json", "")
+	}
+	properties, err := structure.NormalizeJsonString(string(raw))
+	if err != nil {
+		return fmt.Errorf("error normalizing properties returned by the API: %s", err)
+	}
+	return d.Set("properties_json", properties)
+}
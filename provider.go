@@ -2,44 +2,551 @@ package main
 
 import (
 	"context"
+	"net/http"
+	"os"
+	"strings"
+	"time"
 
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func Provider() *schema.Provider {
-	return &schema.Provider{
+	p := &schema.Provider{
 		Schema: map[string]*schema.Schema{
+			"base_url": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("FAXTER_BASE_URL", faxter.DefaultBaseURL),
+				ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+				Description:  "Base URL of the Faxter API, for staging environments or on-prem installations. Defaults to " + faxter.DefaultBaseURL + ".",
+			},
+			"region": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("FAXTER_REGION", nil),
+				Description: "Region of resources that do not set one. Its endpoint is taken from endpoints if listed there, and from base_url otherwise.",
+			},
+			"endpoints": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.IsURLWithHTTPorHTTPS},
+				Description: "Base URL of the API in each region, keyed by region name, so one provider block can manage resources across regions through their region attribute. Every region shares the provider's credentials and settings.",
+			},
 			"token": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				Sensitive:   true,
 				DefaultFunc: schema.EnvDefaultFunc("FAXTER_TOKEN", nil),
-				Description: "The bearer token used for API authentication.",
+				Description: "The bearer token used for API authentication. Required unless refresh_token, oidc_token_file, or signing_key and signing_secret are set.",
+			},
+			"refresh_token": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				DefaultFunc:   schema.EnvDefaultFunc("FAXTER_REFRESH_TOKEN", nil),
+				ConflictsWith: []string{"signing_key"},
+				Description:   "Refresh token exchanged for a new bearer token whenever the API rejects the current one as expired, so long applies keep running. If token is unset, the first bearer token is obtained this way as well.",
+			},
+			"oidc_token_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("FAXTER_OIDC_TOKEN_FILE", nil),
+				ConflictsWith: []string{"token", "refresh_token", "signing_key"},
+				Description:   "Path to an OIDC identity token, e.g. one written by a GitHub Actions or GitLab CI job, exchanged for a bearer token so no static secret is needed. The file is read again whenever a new bearer token is needed.",
+			},
+			"oidc_audience": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("FAXTER_OIDC_AUDIENCE", nil),
+				Description: "Audience the OIDC token was issued for, when the Faxter API trusts more than one.",
+			},
+			"signing_key": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("FAXTER_SIGNING_KEY", nil),
+				RequiredWith: []string{"signing_secret"},
+				Description:  "Key ID for HMAC request signing, used instead of a bearer token on deployments that require signed requests.",
+			},
+			"signing_secret": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				DefaultFunc:  schema.EnvDefaultFunc("FAXTER_SIGNING_SECRET", nil),
+				RequiredWith: []string{"signing_key"},
+				Description:  "Secret used to compute HMAC request signatures.",
+			},
+			"project": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("FAXTER_PROJECT", "default"),
+				Description: "Project used by resources that do not set one.",
+			},
+			"enforce_project": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If true, plans fail for any resource that targets a project other than the provider's project, keeping a module confined to one project.",
+			},
+			"default_flavor": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("FAXTER_DEFAULT_FLAVOR", "copper"),
+				Description: "Flavor used for servers that do not set one explicitly.",
+			},
+			"default_image": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("FAXTER_DEFAULT_IMAGE", "Ubuntu2204"),
+				Description: "Image used for servers that do not set one explicitly.",
+			},
+			"default_networks": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Networks attached to servers and load balancers that do not set any explicitly. Defaults to [\"public1\"].",
+			},
+			"allow_project_rename": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Set to true if the API supports renaming projects. Renames are then applied in place and verified; otherwise changing a project's name replaces it.",
+			},
+			"cache_ttl": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "",
+				ValidateFunc: validateDuration,
+				Description:  "If set (e.g. \"30s\"), data source responses are cached in memory for this long to avoid repeated identical reads during a plan or apply. Caching is off by default.",
+			},
+			"poll_schedule": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateDuration,
+				},
+				Description: "Intervals between status polls while waiting for a resource, e.g. [\"10s\", \"30s\", \"60s\"] (the default). Each interval is used for six polls before moving on to the next; the last one repeats until the wait ends.",
+			},
+			"proxy_url": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsURLWithScheme([]string{"http", "https", "socks5"}),
+				Description:  "Proxy for all API requests, e.g. http://proxy.example.com:3128. When unset, the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables are honored.",
+			},
+			"api_version": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateAPIVersion,
+				Description:  "API version the configuration is written against, e.g. 2.3. Configuring fails unless the API runs the same major version at this minor version or later.",
+			},
+			"app_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Name of the calling application or pipeline, appended to the User-Agent of every API request so the API can attribute traffic to it.",
+			},
+			"extra_headers": {
+				Type:         schema.TypeMap,
+				Optional:     true,
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				ValidateFunc: validateExtraHeaders,
+				Description:  "Headers added to every API request, e.g. an X-Org-Id required by a gateway in front of the API.",
+			},
+			"ca_cert_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("FAXTER_CA_CERT_FILE", nil),
+				Description: "Path to a PEM bundle of additional CA certificates to trust, e.g. for a TLS-intercepting proxy. The system trust store is still used.",
+			},
+			"client_cert_file": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("FAXTER_CLIENT_CERT_FILE", nil),
+				RequiredWith: []string{"client_key_file"},
+				Description:  "Path to a PEM client certificate presented to APIs that require mutual TLS. Bearer token authentication still applies.",
+			},
+			"client_key_file": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("FAXTER_CLIENT_KEY_FILE", nil),
+				RequiredWith: []string{"client_cert_file"},
+				Description:  "Path to the PEM private key of client_cert_file.",
+			},
+			"insecure_skip_verify": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Skip verification of the API's TLS certificate. Only intended for testing.",
+			},
+			"request_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "2m",
+				ValidateFunc: validateDuration,
+				Description:  "Maximum time for a single API request, including reading its response, before it is abandoned (and retried, see max_retries).",
+			},
+			"max_idle_conns_per_host": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      faxter.DefaultMaxIdleConnsPerHost,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Idle connections to the API kept open for reuse. Raise it along with Terraform's -parallelism so that large plans don't keep opening new connections.",
+			},
+			"idle_conn_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "90s",
+				ValidateFunc: validateDuration,
+				Description:  "How long an idle connection to the API is kept open for reuse.",
+			},
+			"requests_per_second": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.FloatAtLeast(0),
+				Description:  "Maximum rate of API requests across all resources, to stay under the API's rate limits during large applies. 0 means unlimited.",
+			},
+			"max_concurrent_requests": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Maximum number of API requests in flight at once across all resources. 0 means unlimited.",
+			},
+			"max_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      3,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "How many times a request is retried after a transient network error (connection reset or refused, timeout, DNS failure), a 429 Too Many Requests, or a 502 or 503, where resending is safe. Creates are only resent after a reset or timeout when the API deduplicates them. Set to 0 to disable retries.",
+			},
+			"circuit_breaker_threshold": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      5,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "After this many requests in a row fail with a network error or a 5xx response, retries included, further requests fail at once without being sent, until the API answers again. This makes an apply against an API that is down fail quickly. Set to 0 to disable.",
+			},
+			"circuit_breaker_cooldown": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "30s",
+				ValidateFunc: validateDuration,
+				Description:  "How long requests are held back once circuit_breaker_threshold is reached before one is sent to check whether the API has recovered.",
+			},
+			"retry_wait_min": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "1s",
+				ValidateFunc: validateDuration,
+				Description:  "Wait before the first retry. The wait doubles on each further retry, and is extended to any Retry-After the API sends.",
+			},
+			"retry_wait_max": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "30s",
+				ValidateFunc: validateDuration,
+				Description:  "Upper bound on the backoff between retries. A longer Retry-After sent by the API is still honoured.",
+			},
+			"error_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      defaultErrorRetries,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Number of consecutive status polls a new server may report \"error\" before its creation or resize fails. The API can flip a server to error briefly while it retries host selection.",
+			},
+			"floating_ip_wait": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "",
+				ValidateFunc: validateDuration,
+				Description:  "If set (e.g. \"10m\"), creating a server or load balancer while the project's floating IP pool is exhausted keeps retrying for this long as addresses are released, instead of failing immediately.",
+			},
+			"read_only": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If true, every create, update and delete fails with an error while reads and data sources keep working, so plans can safely run with production credentials.",
+			},
+			"skip_credentials_validation": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If true, the credentials are not checked against the API when the provider is configured, and an invalid token only surfaces at the first API call.",
+			},
+			"deep_refresh": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If true, reading a resource also checks the objects it depends on (a server's attached volumes, a load balancer's members) and warns about references that no longer resolve. This costs extra API calls per refresh.",
+			},
+			"strict_decoding": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If true, an API response with a field the provider doesn't know, or without one it expects, fails the operation instead of the field being ignored or read as empty. Intended for catching API changes in staging before they corrupt state.",
+			},
+			"tracing": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If true, every resource operation and API call is recorded as an OpenTelemetry span, with the operation, resource name, response status and duration as attributes. Spans are exported over OTLP/HTTP to OTEL_EXPORTER_OTLP_ENDPOINT; without it they are dropped.",
+			},
+			"validate_projects": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If true, check at plan time that the project referenced by each resource exists.",
 			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"faxter_project":        resourceProject(),
-			"faxter_server":         resourceServer(),
-			"faxter_ssh_key":        resourceSSHKey(),
-			"faxter_network":        resourceNetwork(),
-			"faxter_router":         resourceRouter(),
-			"faxter_volume":         resourceVolume(),
-			"faxter_security_group": resourceSecurityGroup(),
-			"faxter_loadbalancer":   resourceLoadBalancer(),
+			"faxter_project":                      resourceProject(),
+			"faxter_server":                       resourceServer(),
+			"faxter_ssh_key":                      resourceSSHKey(),
+			"faxter_network":                      resourceNetwork(),
+			"faxter_router":                       resourceRouter(),
+			"faxter_volume":                       resourceVolume(),
+			"faxter_security_group":               resourceSecurityGroup(),
+			"faxter_security_group_rule":          resourceSecurityGroupRule(),
+			"faxter_loadbalancer":                 resourceLoadBalancer(),
+			"faxter_lb_profile":                   resourceLBProfile(),
+			"faxter_gateway_service":              resourceGatewayService(),
+			"faxter_billing_alert":                resourceBillingAlert(),
+			"faxter_host_aggregate":               resourceHostAggregate(),
+			"faxter_quota_request":                resourceQuotaRequest(),
+			"faxter_qos_policy":                   resourceQoSPolicy(),
+			"faxter_placement_policy":             resourcePlacementPolicy(),
+			"faxter_image_member":                 resourceImageMember(),
+			"faxter_script":                       resourceScript(),
+			"faxter_resource_lock":                resourceResourceLock(),
+			"faxter_reverse_dns":                  resourceReverseDNS(),
+			"faxter_object_storage_bucket_policy": resourceObjectStorageBucketPolicy(),
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"faxter_capacity":                dataSourceCapacity(),
+			"faxter_inventory":               dataSourceInventory(),
+			"faxter_server_effective_config": dataSourceServerEffectiveConfig(),
+			"faxter_server_metrics":          dataSourceServerMetrics(),
+			"faxter_whoami":                  dataSourceWhoami(),
 		},
 		ConfigureContextFunc: providerConfigure,
 	}
+
+	for name, r := range p.ResourcesMap {
+		withRequestHeaders(r)
+		withSelfLink(name, r)
+		guardWrites(name, r)
+		withRegion(r)
+		withTracing(name, r)
+		withStop(r)
+	}
+
+	return p
 }
 
 func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 	var diags diag.Diagnostics
 
-	// Hardcode the base URL here
-	baseURL := "https://api.faxter.com"
+	// Request paths start with a slash, so drop any trailing one here.
+	baseURL := strings.TrimRight(d.Get("base_url").(string), "/")
+	region := d.Get("region").(string)
+	endpoints := map[string]string{}
+	for k, v := range d.Get("endpoints").(map[string]interface{}) {
+		endpoints[k] = v.(string)
+	}
+	if endpoint, ok := endpoints[region]; ok && region != "" {
+		baseURL = strings.TrimRight(endpoint, "/")
+	}
 	token := d.Get("token").(string)
+	signingKey := d.Get("signing_key").(string)
+	signingSecret := d.Get("signing_secret").(string)
+
+	refreshToken := d.Get("refresh_token").(string)
+	oidcTokenFile := d.Get("oidc_token_file").(string)
+
+	if token == "" && signingKey == "" && refreshToken == "" && oidcTokenFile == "" {
+		return nil, diag.Errorf("Either token, refresh_token, oidc_token_file, or signing_key and signing_secret must be configured")
+	}
+	if oidcTokenFile != "" {
+		if _, err := os.Stat(oidcTokenFile); err != nil {
+			return nil, diag.Errorf("Invalid oidc_token_file: %s", err)
+		}
+	}
+
+	retryWaitMin, err := time.ParseDuration(d.Get("retry_wait_min").(string))
+	if err != nil {
+		return nil, diag.Errorf("Invalid retry_wait_min: %s", err)
+	}
+	retryWaitMax, err := time.ParseDuration(d.Get("retry_wait_max").(string))
+	if err != nil {
+		return nil, diag.Errorf("Invalid retry_wait_max: %s", err)
+	}
+	if retryWaitMax < retryWaitMin {
+		return nil, diag.Errorf("retry_wait_max (%s) must not be less than retry_wait_min (%s)", retryWaitMax, retryWaitMin)
+	}
+
+	idleConnTimeout, err := time.ParseDuration(d.Get("idle_conn_timeout").(string))
+	if err != nil {
+		return nil, diag.Errorf("Invalid idle_conn_timeout: %s", err)
+	}
+
+	tc := transportConfig{
+		caCertFile:         d.Get("ca_cert_file").(string),
+		clientCertFile:     d.Get("client_cert_file").(string),
+		clientKeyFile:      d.Get("client_key_file").(string),
+		insecureSkipVerify: d.Get("insecure_skip_verify").(bool),
+		proxyURL:           d.Get("proxy_url").(string),
+
+		maxIdleConnsPerHost: d.Get("max_idle_conns_per_host").(int),
+		idleConnTimeout:     idleConnTimeout,
+	}
+	transport, err := tc.build()
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	if tc.insecureSkipVerify {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "TLS certificate verification is disabled",
+			Detail:   "insecure_skip_verify is set, so the identity of the Faxter API is not verified. Use ca_cert_file to trust a custom CA instead.",
+		})
+	}
+
+	requestTimeout, err := time.ParseDuration(d.Get("request_timeout").(string))
+	if err != nil {
+		return nil, diag.Errorf("Invalid request_timeout: %s", err)
+	}
+
+	headers := http.Header{}
+	for k, v := range d.Get("extra_headers").(map[string]interface{}) {
+		headers.Set(k, v.(string))
+	}
 
 	client := NewClient(baseURL, token)
+	if stop, ok := schema.StopContext(ctx); ok {
+		client.stop = stop
+	}
+	client.rest.Headers = headers
+	client.rest.UserAgent = userAgent(d.Get("app_name").(string))
+	// Token exchanges are not built by newRequest, so hand them the same
+	// headers.
+	authHeaders := headers.Clone()
+	authHeaders.Set("User-Agent", client.rest.UserAgent)
+	client.httpClient.Transport = newTimeoutTransport(requestTimeout, transport)
+	// The limiter sits above the timeout so that time spent queueing for a
+	// turn doesn't count against a request, and below the retries so that
+	// each attempt is paced.
+	requestsPerSecond := d.Get("requests_per_second").(float64)
+	maxConcurrent := d.Get("max_concurrent_requests").(int)
+	if requestsPerSecond > 0 || maxConcurrent > 0 {
+		client.httpClient.Transport = newLimitTransport(requestsPerSecond, maxConcurrent, client.httpClient.Transport)
+	}
+	if refreshToken != "" {
+		client.httpClient.Transport = newTokenRefreshTransport(baseURL, token, refreshToken, authHeaders, client.httpClient.Transport)
+	}
+	if oidcTokenFile != "" {
+		client.httpClient.Transport = newOIDCTransport(baseURL, oidcTokenFile, d.Get("oidc_audience").(string), authHeaders, client.httpClient.Transport)
+	}
+	if signingKey != "" {
+		client.httpClient.Transport = newHMACTransport(signingKey, signingSecret, client.httpClient.Transport)
+	}
+	// Retries wrap the authentication and signing transports, so that each
+	// attempt gets a current token and a fresh timestamp and signature, even
+	// after waiting out a long Retry-After.
+	var retry *retryTransport
+	if maxRetries := d.Get("max_retries").(int); maxRetries > 0 {
+		retry = newRetryTransport(maxRetries, retryWaitMin, retryWaitMax, client.httpClient.Transport)
+		client.httpClient.Transport = retry
+	}
+	// The breaker counts requests, not attempts, so it sits above the
+	// retries.
+	if threshold := d.Get("circuit_breaker_threshold").(int); threshold > 0 {
+		cooldown, err := time.ParseDuration(d.Get("circuit_breaker_cooldown").(string))
+		if err != nil {
+			return nil, diag.Errorf("Invalid circuit_breaker_cooldown: %s", err)
+		}
+		client.httpClient.Transport = newBreakerTransport(threshold, cooldown, client.httpClient.Transport)
+	}
+	client.httpClient.Transport = newLoggingTransport(client.httpClient.Transport)
+	if d.Get("tracing").(bool) {
+		client.tracing = true
+		client.httpClient.Transport = newTracingTransport(client.httpClient.Transport)
+	}
+	client.rest.StrictDecoding = d.Get("strict_decoding").(bool)
+	client.defaultProject = d.Get("project").(string)
+	client.enforceProject = d.Get("enforce_project").(bool)
+	client.defaultFlavor = d.Get("default_flavor").(string)
+	client.defaultImage = d.Get("default_image").(string)
+	client.defaultNetworks = expandStringList(d.Get("default_networks").([]interface{}))
+	if len(client.defaultNetworks) == 0 {
+		client.defaultNetworks = []string{"public1"}
+	}
+	client.validateProjects = d.Get("validate_projects").(bool)
+	client.allowProjectRename = d.Get("allow_project_rename").(bool)
+	client.deepRefresh = d.Get("deep_refresh").(bool)
+	client.readOnly = d.Get("read_only").(bool)
+	client.errorRetries = d.Get("error_retries").(int)
+
+	if raw := expandStringList(d.Get("poll_schedule").([]interface{})); len(raw) > 0 {
+		schedule := make([]time.Duration, len(raw))
+		for i, v := range raw {
+			interval, err := time.ParseDuration(v)
+			if err != nil {
+				return nil, diag.Errorf("Invalid poll_schedule: %s", err)
+			}
+			schedule[i] = interval
+		}
+		client.setPollSchedule(schedule)
+	}
+
+	if wait := d.Get("floating_ip_wait").(string); wait != "" {
+		floatingIPWait, err := time.ParseDuration(wait)
+		if err != nil {
+			return nil, diag.Errorf("Invalid floating_ip_wait: %s", err)
+		}
+		client.floatingIPWait = floatingIPWait
+	}
+
+	if cacheTTL := d.Get("cache_ttl").(string); cacheTTL != "" {
+		ttl, err := time.ParseDuration(cacheTTL)
+		if err != nil {
+			return nil, diag.Errorf("Invalid cache_ttl: %s", err)
+		}
+		client.cache = newResponseCache(ttl)
+	}
+
+	if !d.Get("skip_credentials_validation").(bool) {
+		if err := validateCredentials(ctx, client); err != nil {
+			return nil, diag.FromErr(err)
+		}
+	}
+
+	if err := negotiateAPIVersion(ctx, client, d.Get("api_version").(string)); err != nil {
+		return nil, diag.FromErr(err)
+	}
+
+	// Creates are only retried after a 502 or 503 when the API is known to
+	// deduplicate them; one that merely ignores the key would create twice.
+	if retry != nil && client.capabilities[idempotencyCapability] {
+		retry.idempotencyKeys = true
+	}
+
+	// Regional clients copy the client, so they are made once it is fully
+	// configured.
+	client.region = region
+	client.setEndpoints(endpoints)
 
 	return client, diags
 }
+
+// userAgent returns the User-Agent sent with API requests, e.g.
+// "terraform-provider-faxter/0.1.0 ci-nightly".
+func userAgent(appName string) string {
+	ua := "terraform-provider-faxter/" + version
+	if appName = strings.TrimSpace(appName); appName != "" {
+		ua += " " + appName
+	}
+	return ua
+}
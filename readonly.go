@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// guardWrites wraps a resource's create, update and delete so they fail when
+// the provider is configured with read_only. Reads, imports and data sources
+// are left untouched, which lets plans run with production credentials.
+func guardWrites(name string, r *schema.Resource) {
+	r.CreateContext = rejectIfReadOnly(name, "create", r.CreateContext)
+	r.UpdateContext = rejectIfReadOnly(name, "update", r.UpdateContext)
+	r.DeleteContext = rejectIfReadOnly(name, "delete", r.DeleteContext)
+}
+
+func rejectIfReadOnly[F ~func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics](name, op string, f F) F {
+	if f == nil {
+		return nil
+	}
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		if m.(*Client).readOnly {
+			// A resource being created has no ID yet, so name it by its
+			// name where it has one.
+			id := d.Id()
+			if id == "" {
+				id, _ = d.Get("name").(string)
+			}
+			if id == "" {
+				return diag.Errorf("Cannot %s %s: the provider is configured with read_only = true", op, name)
+			}
+			return diag.Errorf("Cannot %s %s '%s': the provider is configured with read_only = true", op, name, id)
+		}
+		return f(ctx, d, m)
+	}
+}
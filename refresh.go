@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// The checks below run during read when the provider's deep_refresh flag is
+// set. They look up the objects a resource depends on and warn about
+// references that no longer resolve, so the inconsistency shows up at refresh
+// rather than as a failed apply later on. Lookup failures other than a
+// missing object are reported as warnings too; a deep refresh never fails a
+// read on its own.
+
+// checkServerReferences warns about volumes attached to the server that no
+// longer exist.
+func checkServerReferences(ctx context.Context, c *Client, d *schema.ResourceData) diag.Diagnostics {
+	var diags diag.Diagnostics
+	project := d.Get("project").(string)
+
+	for _, volume := range expandStringList(d.Get("volumes").([]interface{})) {
+		exists, err := volumeExists(ctx, c, project, volume)
+		if err != nil {
+			diags = append(diags, deepRefreshWarning(d, fmt.Sprintf("Could not check volume '%s': %s", volume, err)))
+			continue
+		}
+		if !exists {
+			diags = append(diags, deepRefreshWarning(d, fmt.Sprintf("Attached volume '%s' no longer exists in project '%s'.", volume, project)))
+		}
+	}
+
+	return diags
+}
+
+// checkLoadBalancerReferences warns about backend members whose IP no longer
+// belongs to any server in the project.
+func checkLoadBalancerReferences(ctx context.Context, c *Client, d *schema.ResourceData) diag.Diagnostics {
+	var diags diag.Diagnostics
+	project := d.Get("project").(string)
+
+	members := expandServerItems(d.Get("servers").([]interface{}))
+	if len(members) == 0 {
+		return diags
+	}
+
+	servers, err := listServers(ctx, c, project)
+	if err != nil {
+		return append(diags, deepRefreshWarning(d, fmt.Sprintf("Could not list servers to check members: %s", err)))
+	}
+
+	known := make(map[string]bool)
+	for _, server := range servers {
+		for _, ip := range server.Properties.IPAddresses {
+			known[ip] = true
+		}
+	}
+
+	for _, member := range members {
+		if !known[member.IP] {
+			diags = append(diags, deepRefreshWarning(d, fmt.Sprintf("Member %s:%d does not match any server in project '%s'.", member.IP, member.Port, project)))
+		}
+	}
+
+	return diags
+}
+
+func deepRefreshWarning(d *schema.ResourceData, detail string) diag.Diagnostic {
+	return diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("Dangling reference in '%s'", d.Id()),
+		Detail:   detail,
+	}
+}
+
+// volumeExists reports whether the named volume exists in the project.
+func volumeExists(ctx context.Context, c *Client, project, name string) (bool, error) {
+	return objectExists(ctx, c, fmt.Sprintf("/volumes/%s?project_name=%s", url.PathEscape(name), url.QueryEscape(project)))
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// withRegion adds the region attribute to a resource and wraps its CRUD and
+// CustomizeDiff functions so their API calls go to that region's endpoint.
+// Resources that leave region unset are managed in the provider's region.
+func withRegion(r *schema.Resource) {
+	r.Schema["region"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Computed:    true,
+		ForceNew:    true,
+		Description: "Region the object lives in; one of the provider's endpoints. Defaults to the provider's region.",
+	}
+	r.CreateContext = scopeRegion(r.CreateContext)
+	r.ReadContext = scopeRegion(r.ReadContext)
+	r.UpdateContext = scopeRegion(r.UpdateContext)
+	r.DeleteContext = scopeRegion(r.DeleteContext)
+
+	customizeDiff := r.CustomizeDiff
+	r.CustomizeDiff = func(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+		region, _ := d.Get("region").(string)
+		c, err := m.(*Client).inRegion(region)
+		if err != nil {
+			return err
+		}
+		if customizeDiff == nil {
+			return nil
+		}
+		return customizeDiff(ctx, d, c)
+	}
+}
+
+func scopeRegion[F ~func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics](f F) F {
+	if f == nil {
+		return nil
+	}
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		c, err := m.(*Client).inRegion(d.Get("region").(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		// Record the region of objects created or imported without one, so
+		// that changing the provider's region later doesn't lose track of
+		// them.
+		if d.Get("region").(string) == "" && c.region != "" {
+			d.Set("region", c.region)
+		}
+		return f(ctx, d, c)
+	}
+}
+
+// inRegion returns the client for region, or c itself for its own region or
+// an empty one.
+func (c *Client) inRegion(region string) (*Client, error) {
+	if region == "" || region == c.region {
+		return c, nil
+	}
+	if regional, ok := c.regions[region]; ok {
+		return regional, nil
+	}
+
+	known := make([]string, 0, len(c.regions))
+	for name := range c.regions {
+		known = append(known, name)
+	}
+	sort.Strings(known)
+	if len(known) == 0 {
+		return nil, fmt.Errorf("region '%s' has no endpoint: add it to the provider's endpoints", region)
+	}
+	return nil, fmt.Errorf("region '%s' has no endpoint: add it to the provider's endpoints (configured: %s)", region, strings.Join(known, ", "))
+}
+
+// setEndpoints gives c a client for each region in endpoints. The regional
+// clients share c's settings and HTTP client, and so its credentials, but
+// each has its own base URL and status poller.
+func (c *Client) setEndpoints(endpoints map[string]string) {
+	c.regions = make(map[string]*Client, len(endpoints))
+	for region, endpoint := range endpoints {
+		if region == c.region {
+			continue
+		}
+		rest := *c.rest
+		rest.BaseURL = strings.TrimRight(endpoint, "/")
+		regional := c.withREST(&rest)
+		regional.region = region
+		regional.serverStatus = newStatusPoller(regional, c.pollSchedule[0])
+		c.regions[region] = regional
+	}
+}
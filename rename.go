@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// objectExists reports whether a GET of path finds an object.
+func objectExists(ctx context.Context, c *Client, path string) (bool, error) {
+	err := c.api.Do(ctx, "GET", path, nil, nil)
+	if faxter.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// applyRename moves d to newName after the API accepted a rename, once it has
+// checked that the object is reachable under the new name only. Some API
+// deployments implement a rename by creating a new object and leaving the old
+// one behind; moving state silently would then orphan the old object.
+func applyRename(ctx context.Context, c *Client, d *schema.ResourceData, kind string, path func(name string) string, oldName, newName string) diag.Diagnostics {
+	if oldName == newName {
+		return nil
+	}
+
+	newExists, err := objectExists(ctx, c, path(newName))
+	if err != nil {
+		return apiErrorDiag(fmt.Sprintf("Failed to verify rename of %s '%s'", kind, oldName), err)
+	}
+	if !newExists {
+		return diag.Errorf("The %s '%s' was not renamed to '%s': the API accepted the request but nothing exists under the new name.", kind, oldName, newName)
+	}
+
+	// The object under the new name is the one matching the configuration.
+	d.SetId(newName)
+
+	oldExists, err := objectExists(ctx, c, path(oldName))
+	if err != nil {
+		return apiErrorDiag(fmt.Sprintf("Failed to verify rename of %s '%s'", kind, oldName), err)
+	}
+	if oldExists {
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  "Rename left a duplicate " + kind,
+			Detail:   "Renaming " + kind + " '" + oldName + "' to '" + newName + "' created a new object and left the old one in place. Terraform now tracks '" + newName + "'; delete '" + oldName + "' or import it into a separate resource.",
+		}}
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceBillingAlert() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceBillingAlertCreate,
+		ReadContext:   resourceBillingAlertRead,
+		UpdateContext: resourceBillingAlertUpdate,
+		DeleteContext: resourceBillingAlertDelete,
+		CustomizeDiff: customizeDiffProject,
+		Importer: &schema.ResourceImporter{
+			StateContext: importProjectScoped,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "Project whose spend is watched.",
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateName,
+			},
+			"monthly_threshold": {
+				Type:         schema.TypeFloat,
+				Required:     true,
+				ValidateFunc: validation.FloatAtLeast(0.01),
+				Description:  "Month-to-date spend, in the account's billing currency, at which the alert fires.",
+			},
+			"notification_channel": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				Description:  "Where the alert is sent, e.g. an email address or webhook URL.",
+			},
+		},
+	}
+}
+
+func billingAlertPath(project, name string) string {
+	return fmt.Sprintf("/billing/alerts/%s?project_name=%s", url.PathEscape(name), url.QueryEscape(project))
+}
+
+func expandBillingAlert(d *schema.ResourceData) *faxter.BillingAlertRequest {
+	return &faxter.BillingAlertRequest{
+		Project:             d.Get("project").(string),
+		Name:                d.Get("name").(string),
+		MonthlyThreshold:    d.Get("monthly_threshold").(float64),
+		NotificationChannel: d.Get("notification_channel").(string),
+	}
+}
+
+func resourceBillingAlertCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	reqData := expandBillingAlert(d)
+	if err := c.api.CreateBillingAlert(ctx, reqData); err != nil {
+		return apiErrorDiag("Failed to create billing alert", err)
+	}
+
+	d.SetId(reqData.Name)
+	return resourceBillingAlertRead(ctx, d, m)
+}
+
+func resourceBillingAlertRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+	var diags diag.Diagnostics
+
+	alert, err := c.api.GetBillingAlert(ctx, d.Get("project").(string), d.Id())
+	if faxter.IsNotFound(err) {
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return apiErrorDiag("Failed to read billing alert", err)
+	}
+
+	d.Set("name", d.Id())
+	d.Set("monthly_threshold", alert.MonthlyThreshold)
+	d.Set("notification_channel", alert.NotificationChannel)
+
+	return diags
+}
+
+func resourceBillingAlertUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	err := c.api.UpdateBillingAlert(ctx, d.Get("project").(string), d.Id(), expandBillingAlert(d))
+	if faxter.IsNotFound(err) {
+		return resourceGone(d, "billing alert")
+	}
+	if err != nil {
+		return apiErrorDiag("Failed to update billing alert", err)
+	}
+
+	return resourceBillingAlertRead(ctx, d, m)
+}
+
+func resourceBillingAlertDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+	var diags diag.Diagnostics
+
+	err := c.api.DeleteBillingAlert(ctx, d.Get("project").(string), d.Id())
+	if faxter.IsNotFound(err) {
+		return resourceGone(d, "billing alert")
+	}
+	if err != nil {
+		return apiErrorDiag("Failed to delete billing alert", err)
+	}
+
+	d.SetId("")
+	return diags
+}
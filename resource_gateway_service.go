@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceGatewayService() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceGatewayServiceCreate,
+		ReadContext:   resourceGatewayServiceRead,
+		UpdateContext: resourceGatewayServiceUpdate,
+		DeleteContext: resourceGatewayServiceDelete,
+		CustomizeDiff: customizeDiffProject,
+		Importer: &schema.ResourceImporter{
+			StateContext: importProjectScoped,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateName,
+			},
+			"network": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Private network whose subnets egress through the gateway.",
+			},
+			"bandwidth_tier": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Bandwidth tier of the gateway. The API default is used when unset.",
+			},
+			"external_ip": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Public address outbound traffic is translated to.",
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceGatewayServiceCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	reqData := &faxter.GatewayServiceRequest{
+		Project:       d.Get("project").(string),
+		Name:          d.Get("name").(string),
+		Network:       d.Get("network").(string),
+		BandwidthTier: d.Get("bandwidth_tier").(string),
+	}
+
+	if err := c.api.CreateGatewayService(ctx, reqData); err != nil {
+		return apiErrorDiag("Failed to create gateway service", err)
+	}
+
+	d.SetId(reqData.Name)
+	return resourceGatewayServiceRead(ctx, d, m)
+}
+
+func resourceGatewayServiceRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+	var diags diag.Diagnostics
+
+	gateway, err := c.api.GetGatewayService(ctx, d.Get("project").(string), d.Id())
+	if faxter.IsNotFound(err) {
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return apiErrorDiag("Failed to read gateway service", err)
+	}
+
+	d.Set("name", d.Id())
+	if gateway.Network != "" {
+		d.Set("network", gateway.Network)
+	}
+	d.Set("bandwidth_tier", gateway.BandwidthTier)
+	d.Set("external_ip", gateway.ExternalIP)
+	d.Set("status", gateway.Status)
+
+	return diags
+}
+
+func resourceGatewayServiceUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	reqData := &faxter.GatewayServiceUpdateRequest{
+		BandwidthTier: d.Get("bandwidth_tier").(string),
+	}
+
+	err := c.api.UpdateGatewayService(ctx, d.Get("project").(string), d.Id(), reqData)
+	if faxter.IsNotFound(err) {
+		return resourceGone(d, "gateway service")
+	}
+	if err != nil {
+		return apiErrorDiag("Failed to update gateway service", err)
+	}
+
+	return resourceGatewayServiceRead(ctx, d, m)
+}
+
+func resourceGatewayServiceDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+	var diags diag.Diagnostics
+
+	err := c.api.DeleteGatewayService(ctx, d.Get("project").(string), d.Id())
+	if faxter.IsNotFound(err) {
+		return resourceGone(d, "gateway service")
+	}
+	if err != nil {
+		return apiErrorDiag("Failed to delete gateway service", err)
+	}
+
+	d.SetId("")
+	return diags
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceHostAggregate manages a pool of hypervisors on private deployments.
+// Servers are pinned to a pool through their scheduler_hints.
+func resourceHostAggregate() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceHostAggregateCreate,
+		ReadContext:   resourceHostAggregateRead,
+		UpdateContext: resourceHostAggregateUpdate,
+		DeleteContext: resourceHostAggregateDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateName,
+			},
+			"availability_zone": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Availability zone exposed for the aggregate's hosts.",
+			},
+			"hosts": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Hypervisor hostnames in the pool.",
+			},
+			"metadata": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Key/value pairs the scheduler matches against server scheduler_hints, e.g. { license = \"oracle\" }.",
+			},
+		},
+	}
+}
+
+func hostAggregatePath(name string) string {
+	return fmt.Sprintf("/host_aggregates/%s", url.PathEscape(name))
+}
+
+func expandHostAggregate(d *schema.ResourceData) *faxter.HostAggregateRequest {
+	hosts := expandStringList(d.Get("hosts").(*schema.Set).List())
+	if hosts == nil {
+		hosts = []string{}
+	}
+	return &faxter.HostAggregateRequest{
+		Name:             d.Get("name").(string),
+		AvailabilityZone: d.Get("availability_zone").(string),
+		Hosts:            hosts,
+		Metadata:         expandStringMap(d.Get("metadata").(map[string]interface{})),
+	}
+}
+
+func resourceHostAggregateCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	reqData := expandHostAggregate(d)
+	if err := c.api.CreateHostAggregate(ctx, reqData); err != nil {
+		return apiErrorDiag("Failed to create host aggregate", err)
+	}
+
+	d.SetId(reqData.Name)
+	return resourceHostAggregateRead(ctx, d, m)
+}
+
+func resourceHostAggregateRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+	var diags diag.Diagnostics
+
+	aggregate, err := c.api.GetHostAggregate(ctx, d.Id())
+	if faxter.IsNotFound(err) {
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return apiErrorDiag("Failed to read host aggregate", err)
+	}
+
+	d.Set("name", d.Id())
+	d.Set("availability_zone", aggregate.AvailabilityZone)
+	if err := d.Set("hosts", aggregate.Hosts); err != nil {
+		return diag.Errorf("Error setting hosts: %s", err)
+	}
+	if err := d.Set("metadata", aggregate.Metadata); err != nil {
+		return diag.Errorf("Error setting metadata: %s", err)
+	}
+
+	return diags
+}
+
+func resourceHostAggregateUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	err := c.api.UpdateHostAggregate(ctx, d.Id(), expandHostAggregate(d))
+	if faxter.IsNotFound(err) {
+		return resourceGone(d, "host aggregate")
+	}
+	if err != nil {
+		return apiErrorDiag("Failed to update host aggregate", err)
+	}
+
+	return resourceHostAggregateRead(ctx, d, m)
+}
+
+func resourceHostAggregateDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+	var diags diag.Diagnostics
+
+	err := c.api.DeleteHostAggregate(ctx, d.Id())
+	if faxter.IsNotFound(err) {
+		return resourceGone(d, "host aggregate")
+	}
+	if err != nil {
+		return apiErrorDiag("Failed to delete host aggregate", err)
+	}
+
+	d.SetId("")
+	return diags
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// resourceImageMember shares a custom image owned by project with another
+// project, e.g. a golden image built in a tooling project. The member project
+// accepts or rejects the share through status.
+func resourceImageMember() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceImageMemberCreate,
+		ReadContext:   resourceImageMemberRead,
+		UpdateContext: resourceImageMemberUpdate,
+		DeleteContext: resourceImageMemberDelete,
+		CustomizeDiff: customizeDiffProject,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceImageMemberImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "Project that owns the image.",
+			},
+			"image": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Name of the custom image to share.",
+			},
+			"member_project": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Project the image is shared with.",
+			},
+			"status": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice([]string{"accepted", "rejected"}, false),
+				Description:  "Set to accepted or rejected to answer the share on behalf of the member project; the credentials must have access to it. Left unset, the share stays pending until the member project answers.",
+			},
+		},
+	}
+}
+
+func resourceImageMemberCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	project := d.Get("project").(string)
+	image := d.Get("image").(string)
+	member := d.Get("member_project").(string)
+
+	if _, err := c.api.CreateImageMember(ctx, project, image, member); err != nil {
+		return apiErrorDiag(fmt.Sprintf("Failed to share image '%s' with project '%s'", image, member), err)
+	}
+	d.SetId(image + "/" + member)
+
+	if status := d.Get("status").(string); status != "" {
+		if err := c.api.SetImageMemberStatus(ctx, image, member, status); err != nil {
+			return apiErrorDiag("Failed to set image member status", err)
+		}
+	}
+
+	return resourceImageMemberRead(ctx, d, m)
+}
+
+func resourceImageMemberRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+	var diags diag.Diagnostics
+
+	imageMember, err := c.api.GetImageMember(ctx, d.Get("project").(string), d.Get("image").(string), d.Get("member_project").(string))
+	if faxter.IsNotFound(err) {
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return apiErrorDiag("Failed to read image member", err)
+	}
+
+	d.Set("status", imageMember.Status)
+	return diags
+}
+
+func resourceImageMemberUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	if status := d.Get("status").(string); d.HasChange("status") && status != "" {
+		err := c.api.SetImageMemberStatus(ctx, d.Get("image").(string), d.Get("member_project").(string), status)
+		if faxter.IsNotFound(err) {
+			return resourceGone(d, "image member")
+		}
+		if err != nil {
+			return apiErrorDiag("Failed to set image member status", err)
+		}
+	}
+
+	return resourceImageMemberRead(ctx, d, m)
+}
+
+func resourceImageMemberDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+	var diags diag.Diagnostics
+
+	err := c.api.DeleteImageMember(ctx, d.Get("project").(string), d.Get("image").(string), d.Get("member_project").(string))
+	if faxter.IsNotFound(err) {
+		return resourceGone(d, "image member")
+	}
+	if err != nil {
+		return apiErrorDiag("Failed to delete image member", err)
+	}
+
+	d.SetId("")
+	return diags
+}
+
+// resourceImageMemberImport accepts "<project>/<image>/<member_project>", or
+// "<image>/<member_project>" for an image in the provider's project.
+func resourceImageMemberImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	c := m.(*Client)
+
+	parts := strings.Split(d.Id(), "/")
+	if len(parts) == 2 {
+		parts = append([]string{c.defaultProject}, parts...)
+	}
+	if len(parts) != 3 || parts[1] == "" || parts[2] == "" {
+		return nil, fmt.Errorf("unexpected import ID %q, expected <project>/<image>/<member_project> or <image>/<member_project>", d.Id())
+	}
+
+	d.SetId(parts[1] + "/" + parts[2])
+	d.Set("project", parts[0])
+	d.Set("image", parts[1])
+	d.Set("member_project", parts[2])
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// lbProfileCapability is reported by APIs that support LB profiles.
+const lbProfileCapability = "lb_profiles"
+
+// resourceLBProfile manages the listener timeouts, retries and health check
+// that load balancers refer to with profile, so that many load balancers
+// share one tuned set of values.
+func resourceLBProfile() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceLBProfileCreate,
+		ReadContext:   resourceLBProfileRead,
+		UpdateContext: resourceLBProfileUpdate,
+		DeleteContext: resourceLBProfileDelete,
+		CustomizeDiff: customdiff.All(
+			customizeDiffProject,
+			func(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+				return m.(*Client).requireCapability(lbProfileCapability, "faxter_lb_profile")
+			},
+		),
+		Importer: &schema.ResourceImporter{
+			StateContext: importProjectScoped,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateName,
+			},
+			"client_timeout": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Seconds a client connection to the listener may sit idle. Defaults to the API's setting.",
+			},
+			"connect_timeout": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Seconds allowed for opening a connection to a backend server. Defaults to the API's setting.",
+			},
+			"member_timeout": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Seconds a connection to a backend server may sit idle. Defaults to the API's setting.",
+			},
+			"retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntBetween(0, 10),
+				Description:  "Further backend servers tried before a request fails. Defaults to the API's setting.",
+			},
+			"health_check": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"protocol": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "tcp",
+							ValidateFunc: validation.StringInSlice([]string{"tcp", "http", "https"}, false),
+						},
+						"path": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Path requested by http and https checks, e.g. /healthz.",
+						},
+						"interval": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      10,
+							ValidateFunc: validation.IntAtLeast(1),
+							Description:  "Seconds between checks.",
+						},
+						"timeout": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      5,
+							ValidateFunc: validation.IntAtLeast(1),
+							Description:  "Seconds a check may take.",
+						},
+						"healthy_threshold": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      2,
+							ValidateFunc: validation.IntAtLeast(1),
+							Description:  "Consecutive successful checks before a backend server is put back in rotation.",
+						},
+						"unhealthy_threshold": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      3,
+							ValidateFunc: validation.IntAtLeast(1),
+							Description:  "Consecutive failed checks before a backend server is taken out of rotation.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func expandLBProfile(d *schema.ResourceData) *faxter.LBProfileRequest {
+	reqData := &faxter.LBProfileRequest{
+		Project:               d.Get("project").(string),
+		Name:                  d.Get("name").(string),
+		ClientTimeoutSeconds:  d.Get("client_timeout").(int),
+		ConnectTimeoutSeconds: d.Get("connect_timeout").(int),
+		MemberTimeoutSeconds:  d.Get("member_timeout").(int),
+		Retries:               d.Get("retries").(int),
+	}
+	if checks := d.Get("health_check").([]interface{}); len(checks) > 0 && checks[0] != nil {
+		check := checks[0].(map[string]interface{})
+		reqData.HealthCheck = &faxter.LBHealthCheck{
+			Protocol:           check["protocol"].(string),
+			Path:               check["path"].(string),
+			IntervalSeconds:    check["interval"].(int),
+			TimeoutSeconds:     check["timeout"].(int),
+			HealthyThreshold:   check["healthy_threshold"].(int),
+			UnhealthyThreshold: check["unhealthy_threshold"].(int),
+		}
+	}
+	return reqData
+}
+
+func flattenLBHealthCheck(check *faxter.LBHealthCheck) []interface{} {
+	if check == nil {
+		return nil
+	}
+	return []interface{}{map[string]interface{}{
+		"protocol":            check.Protocol,
+		"path":                check.Path,
+		"interval":            check.IntervalSeconds,
+		"timeout":             check.TimeoutSeconds,
+		"healthy_threshold":   check.HealthyThreshold,
+		"unhealthy_threshold": check.UnhealthyThreshold,
+	}}
+}
+
+func resourceLBProfileCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	reqData := expandLBProfile(d)
+	if err := c.api.CreateLBProfile(ctx, reqData); err != nil {
+		return apiErrorDiag("Failed to create LB profile", err)
+	}
+
+	d.SetId(reqData.Name)
+	return resourceLBProfileRead(ctx, d, m)
+}
+
+func resourceLBProfileRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+	var diags diag.Diagnostics
+
+	profile, err := c.api.GetLBProfile(ctx, d.Get("project").(string), d.Id())
+	if faxter.IsNotFound(err) {
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return apiErrorDiag("Failed to read LB profile", err)
+	}
+
+	d.Set("name", d.Id())
+	d.Set("client_timeout", profile.ClientTimeoutSeconds)
+	d.Set("connect_timeout", profile.ConnectTimeoutSeconds)
+	d.Set("member_timeout", profile.MemberTimeoutSeconds)
+	d.Set("retries", profile.Retries)
+	if err := d.Set("health_check", flattenLBHealthCheck(profile.HealthCheck)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
+
+func resourceLBProfileUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	err := c.api.UpdateLBProfile(ctx, d.Get("project").(string), d.Id(), expandLBProfile(d))
+	if faxter.IsNotFound(err) {
+		return resourceGone(d, "LB profile")
+	}
+	if err != nil {
+		return apiErrorDiag("Failed to update LB profile", err)
+	}
+
+	return resourceLBProfileRead(ctx, d, m)
+}
+
+func resourceLBProfileDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+	var diags diag.Diagnostics
+
+	err := c.api.DeleteLBProfile(ctx, d.Get("project").(string), d.Id())
+	if faxter.IsNotFound(err) {
+		return resourceGone(d, "LB profile")
+	}
+	if err != nil {
+		return apiErrorDiag("Failed to delete LB profile", err)
+	}
+
+	d.SetId("")
+	return diags
+}
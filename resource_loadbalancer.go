@@ -1,93 +1,71 @@
 package main
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"net/url"
+	"net/http"
+	"sync"
 
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
-// ServerItem represents a single backend server object for the load balancer.
-type ServerItem struct {
-	IP       string `json:"ip"`
-	Port     int    `json:"port"`
-	Endpoint string `json:"endpoint"`
-}
-
-type LoadBalancerCreateRequest struct {
-	Project           string       `json:"project,omitempty"`
-	Name              string       `json:"name"`
-	Port              int          `json:"port,omitempty"`
-	Networks          []string     `json:"networks,omitempty"`
-	SubNetworks       []string     `json:"sub_networks,omitempty"`
-	KeyName           string       `json:"key_name,omitempty"`
-	RequestFloatingIP bool         `json:"request_floating_ip,omitempty"`
-	SSLEnabled        bool         `json:"ssl_enabled,omitempty"`
-	Servers           []ServerItem `json:"servers,omitempty"`
-	SecurityGroups    []string     `json:"security_groups,omitempty"`
-}
-
-// If your API has a separate "Update" schema, define it similarly.
-// For simplicity, we'll reuse a structure, but typically you'd have a separate struct.
-type LoadBalancerUpdateRequest struct {
-	Name              string        `json:"name"` // required
-	Port              *int          `json:"port,omitempty"`
-	Networks          *[]string     `json:"networks,omitempty"`
-	SubNetworks       *[]string     `json:"sub_networks,omitempty"`
-	KeyName           *string       `json:"key_name,omitempty"`
-	RequestFloatingIP *bool         `json:"request_floating_ip,omitempty"`
-	SSLEnabled        *bool         `json:"ssl_enabled,omitempty"`
-	Servers           *[]ServerItem `json:"servers,omitempty"`
-	SecurityGroups    *[]string     `json:"security_groups,omitempty"`
-}
-
-// The API response might look like a ResourceResponse, or a custom LB struct
-type LoadBalancerResponse struct {
-	Name       string `json:"name"`
-	Status     string `json:"status"`
-	Properties struct {
-		// Possibly more detail here if your API returns it
-	} `json:"properties"`
-}
-
 func resourceLoadBalancer() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceLoadBalancerCreate,
 		ReadContext:   resourceLoadBalancerRead,
 		UpdateContext: resourceLoadBalancerUpdate,
 		DeleteContext: resourceLoadBalancerDelete,
+		CustomizeDiff: customdiff.All(
+			customizeDiffProject,
+			customizeDiffLoadBalancer,
+		),
+		Importer: &schema.ResourceImporter{
+			StateContext: importProjectScoped,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"project": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Default:     "default",
+				Computed:    true,
 				Description: "Name of the Faxter project in which to create the load balancer.",
 			},
 			"name": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "Unique name of the load balancer.",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateName,
+				Description:  "Unique name of the load balancer.",
 			},
 			"port": {
-				Type:        schema.TypeInt,
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      80,
+				ValidateFunc: validation.IsPortNumber,
+				Description:  "The port on which the load balancer listens.",
+			},
+			"allowed_cidrs": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.IsCIDR},
+				Description: "Source CIDRs allowed to connect to the listener on port. The API rejects all other sources; leave empty to allow any source.",
+			},
+			"profile": {
+				Type:        schema.TypeString,
 				Optional:    true,
-				Default:     80,
-				Description: "The port on which the load balancer listens.",
+				Description: "Name of a faxter_lb_profile whose timeouts, retries and health check the listener uses. Leave empty for the API's defaults.",
 			},
 			"networks": {
-				Type:     schema.TypeList,
-				Optional: true,
-				Elem:     &schema.Schema{Type: schema.TypeString},
-				DefaultFunc: func() (interface{}, error) {
-					return []interface{}{"public1"}, nil
-				},
-				Description: "List of networks to which the load balancer is attached.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "List of networks to which the load balancer is attached. Defaults to the provider's default_networks.",
 			},
 			"sub_networks": {
 				Type:     schema.TypeList,
@@ -98,9 +76,10 @@ func resourceLoadBalancer() *schema.Resource {
 				},
 			},
 			"key_name": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "Optional SSH key name used if the LB runs in a VM-based context.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: suppressEquivalentReference,
+				Description:      "Optional SSH key name used if the LB runs in a VM-based context.",
 			},
 			"request_floating_ip": {
 				Type:        schema.TypeBool,
@@ -112,11 +91,36 @@ func resourceLoadBalancer() *schema.Resource {
 				Type:        schema.TypeBool,
 				Optional:    true,
 				Default:     false,
-				Description: "If true, the load balancer will terminate SSL.",
+				Description: "If true, the load balancer will terminate SSL. Requires certificate and private_key.",
+			},
+			"certificate": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "PEM-encoded certificate chain presented when ssl_enabled is true.",
+			},
+			"private_key": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Sensitive: true,
+				StateFunc: hashSecret,
+				// The key is never read back from the API; state only holds
+				// its SHA-256 so that a changed key is still detected.
+				Description: "PEM-encoded private key for certificate. Write-only: only a SHA-256 hash of the key is stored in state.",
+			},
+			"certificate_fingerprint": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "SHA-256 fingerprint of the leaf certificate.",
+			},
+			"certificate_expiry": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Expiry of the leaf certificate (RFC 3339).",
 			},
 			"servers": {
 				Type:        schema.TypeList,
 				Required:    true,
+				MinItems:    1,
 				Description: "List of backend server objects for this load balancer.",
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
@@ -126,9 +130,10 @@ func resourceLoadBalancer() *schema.Resource {
 							Description: "IP address of the backend server.",
 						},
 						"port": {
-							Type:        schema.TypeInt,
-							Required:    true,
-							Description: "Port of the backend server.",
+							Type:         schema.TypeInt,
+							Required:     true,
+							ValidateFunc: validation.IsPortNumber,
+							Description:  "Port of the backend server.",
 						},
 						"endpoint": {
 							Type:        schema.TypeString,
@@ -153,6 +158,7 @@ func resourceLoadBalancer() *schema.Resource {
 				Computed:    true,
 				Description: "Current status of the load balancer (if returned by the API).",
 			},
+			"properties_json": propertiesJSONSchema(),
 		},
 	}
 }
@@ -165,14 +171,21 @@ func resourceLoadBalancerCreate(ctx context.Context, d *schema.ResourceData, m i
 	name := d.Get("name").(string)
 	port := d.Get("port").(int)
 	networks := expandStringList(d.Get("networks").([]interface{}))
+	if len(networks) == 0 {
+		networks = c.defaultNetworks
+		d.Set("networks", networks)
+	}
 	sub_networks := expandStringList(d.Get("sub_networks").([]interface{}))
 	keyName := d.Get("key_name").(string)
 	requestFloatingIP := d.Get("request_floating_ip").(bool)
 	sslEnabled := d.Get("ssl_enabled").(bool)
+	certificate := d.Get("certificate").(string)
+	privateKey := d.Get("private_key").(string)
 	servers := expandServerItems(d.Get("servers").([]interface{}))
 	securityGroups := expandStringList(d.Get("security_groups").([]interface{}))
+	allowedCIDRs := expandStringList(d.Get("allowed_cidrs").([]interface{}))
 
-	reqData := &LoadBalancerCreateRequest{
+	reqData := &faxter.LoadBalancerCreateRequest{
 		Project:           project,
 		Name:              name,
 		Port:              port,
@@ -181,32 +194,20 @@ func resourceLoadBalancerCreate(ctx context.Context, d *schema.ResourceData, m i
 		KeyName:           keyName,
 		RequestFloatingIP: requestFloatingIP,
 		SSLEnabled:        sslEnabled,
+		Certificate:       certificate,
+		PrivateKey:        privateKey,
 		Servers:           servers,
 		SecurityGroups:    securityGroups,
+		AllowedCIDRs:      allowedCIDRs,
+		Profile:           d.Get("profile").(string),
 	}
 
-	bodyBytes, _ := json.Marshal(reqData)
-	req, err := c.newRequest("POST", "/loadbalancers/")
-	if err != nil {
-		return diag.FromErr(err)
-	}
-	req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
-
-	resp, err := c.httpClient.Do(req.WithContext(ctx))
-	if err != nil {
-		return diag.FromErr(err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		body, _ := io.ReadAll(resp.Body)
-		return diag.Errorf("Failed to create load balancer: %s - %s", resp.Status, string(body))
-	}
-
-	var lbResp LoadBalancerResponse
-	err = json.NewDecoder(resp.Body).Decode(&lbResp)
-	if err != nil {
-		return diag.FromErr(err)
+	var lbResp *faxter.LoadBalancerResponse
+	if reqDiags := doFloatingIPRequest(ctx, c, project, "create load balancer", func() (err error) {
+		lbResp, err = c.api.CreateLoadBalancer(ctx, reqData)
+		return err
+	}); reqDiags.HasError() {
+		return reqDiags
 	}
 
 	// Use the name from the response as the Terraform ID
@@ -214,6 +215,9 @@ func resourceLoadBalancerCreate(ctx context.Context, d *schema.ResourceData, m i
 
 	// If the API returns a status, record it
 	_ = d.Set("status", lbResp.Status)
+	if err := setPropertiesJSON(d, lbResp.RawProperties); err != nil {
+		return diag.FromErr(err)
+	}
 
 	return diags
 }
@@ -222,39 +226,24 @@ func resourceLoadBalancerRead(ctx context.Context, d *schema.ResourceData, m int
 	c := m.(*Client)
 	var diags diag.Diagnostics
 
-	project := d.Get("project").(string)
-	name := d.Id()
-
-	path := fmt.Sprintf("/loadbalancers/%s?project_name=%s", url.PathEscape(name), url.PathEscape(project))
-	req, err := c.newRequest("GET", path)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	resp, err := c.httpClient.Do(req.WithContext(ctx))
-	if err != nil {
-		return diag.FromErr(err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode == 404 {
+	lbResp, err := c.api.GetLoadBalancer(ctx, d.Get("project").(string), d.Id())
+	if faxter.IsNotFound(err) {
 		d.SetId("")
 		return diags
 	}
-
-	if resp.StatusCode != 200 {
-		body, _ := io.ReadAll(resp.Body)
-		return diag.Errorf("Failed to read load balancer: %s - %s", resp.Status, string(body))
-	}
-
-	var lbResp LoadBalancerResponse
-	err = json.NewDecoder(resp.Body).Decode(&lbResp)
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiag("Failed to read load balancer", err)
 	}
 
 	// Update any known fields. The API might not return all fields; if so, we skip updating them.
 	_ = d.Set("status", lbResp.Status)
+	if err := setPropertiesJSON(d, lbResp.RawProperties); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if c.deepRefresh {
+		diags = append(diags, checkLoadBalancerReferences(ctx, c, d)...)
+	}
 
 	return diags
 }
@@ -267,7 +256,7 @@ func resourceLoadBalancerUpdate(ctx context.Context, d *schema.ResourceData, m i
 	project := d.Get("project").(string)
 	newName := d.Get("name").(string)
 
-	updateReq := &LoadBalancerUpdateRequest{
+	updateReq := &faxter.LoadBalancerUpdateRequest{
 		Name: newName, // The API requires name
 	}
 
@@ -295,36 +284,61 @@ func resourceLoadBalancerUpdate(ctx context.Context, d *schema.ResourceData, m i
 		newSSL := d.Get("ssl_enabled").(bool)
 		updateReq.SSLEnabled = &newSSL
 	}
-	if d.HasChange("servers") {
-		newServers := expandServerItems(d.Get("servers").([]interface{}))
-		updateReq.Servers = &newServers
+	if d.HasChange("certificate") {
+		newCert := d.Get("certificate").(string)
+		updateReq.Certificate = &newCert
+	}
+	if d.HasChange("private_key") {
+		newKey := d.Get("private_key").(string)
+		updateReq.PrivateKey = &newKey
 	}
 	if d.HasChange("security_groups") {
 		newSGs := expandStringList(d.Get("security_groups").([]interface{}))
 		updateReq.SecurityGroups = &newSGs
 	}
-
-	bodyBytes, _ := json.Marshal(updateReq)
-	path := fmt.Sprintf("/loadbalancers/%s?project_name=%s", url.PathEscape(oldName), url.PathEscape(project))
-	req, err := c.newRequest("PUT", path)
-	if err != nil {
-		return diag.FromErr(err)
+	if d.HasChange("allowed_cidrs") {
+		newCIDRs := expandStringList(d.Get("allowed_cidrs").([]interface{}))
+		if newCIDRs == nil {
+			newCIDRs = []string{}
+		}
+		updateReq.AllowedCIDRs = &newCIDRs
 	}
-	req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
-
-	resp, err := c.httpClient.Do(req.WithContext(ctx))
-	if err != nil {
-		return diag.FromErr(err)
+	if d.HasChange("profile") {
+		newProfile := d.Get("profile").(string)
+		updateReq.Profile = &newProfile
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		body, _ := io.ReadAll(resp.Body)
-		return diag.Errorf("Failed to update load balancer: %s - %s", resp.Status, string(body))
+	// Backend members are reconciled separately below, so the full update is
+	// only sent when something other than the member list changed.
+	if d.HasChangesExcept("servers") {
+		err := c.api.UpdateLoadBalancer(ctx, project, oldName, updateReq)
+		if faxter.IsNotFound(err) {
+			return resourceGone(d, "load balancer")
+		}
+		if err != nil {
+			return apiErrorDiag("Failed to update load balancer", err)
+		}
+
+		// If the name changed, update the ID
+		renameDiags := applyRename(ctx, c, d, "load balancer", func(name string) string {
+			return faxter.ObjectPath("loadbalancers", project, name)
+		}, oldName, newName)
+		if renameDiags.HasError() {
+			return renameDiags
+		}
 	}
 
-	// If the name changed, update the ID
-	d.SetId(newName)
+	// Add and remove individual members rather than re-sending the whole list,
+	// which the API would otherwise process serially.
+	if d.HasChange("servers") {
+		oldServers, newServers := d.GetChange("servers")
+		err := updateLoadBalancerMembers(ctx, c, project, newName,
+			expandServerItems(oldServers.([]interface{})),
+			expandServerItems(newServers.([]interface{})))
+		if err != nil {
+			return apiErrorDiag("Failed to update load balancer members", err)
+		}
+	}
 
 	return diags
 }
@@ -333,36 +347,80 @@ func resourceLoadBalancerDelete(ctx context.Context, d *schema.ResourceData, m i
 	c := m.(*Client)
 	var diags diag.Diagnostics
 
-	name := d.Id()
-	project := d.Get("project").(string)
-	path := fmt.Sprintf("/loadbalancers/%s?project_name=%s", url.PathEscape(name), url.PathEscape(project))
-
-	req, err := c.newRequest("DELETE", path)
+	err := c.api.DeleteLoadBalancer(ctx, d.Get("project").(string), d.Id())
+	if faxter.IsNotFound(err) {
+		return resourceGone(d, "load balancer")
+	}
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiag("Failed to delete load balancer", err)
 	}
 
-	resp, err := c.httpClient.Do(req.WithContext(ctx))
-	if err != nil {
-		return diag.FromErr(err)
+	d.SetId("")
+	return diags
+}
+
+// customizeDiffLoadBalancer catches configurations the API would reject with a
+// 422, using values only known at plan time (e.g. a servers list built with a
+// for expression that turns out empty).
+func customizeDiffLoadBalancer(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	c := m.(*Client)
+
+	// Source restrictions arrived in a later API release; fail the plan
+	// rather than have an older API ignore them.
+	if len(d.Get("allowed_cidrs").([]interface{})) > 0 {
+		if err := c.requireCapability("loadbalancer_allowed_cidrs", "allowed_cidrs"); err != nil {
+			return err
+		}
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		body, _ := io.ReadAll(resp.Body)
-		return diag.Errorf("Failed to delete load balancer: %s - %s", resp.Status, string(body))
+	if d.Get("profile").(string) != "" {
+		if err := c.requireCapability(lbProfileCapability, "profile"); err != nil {
+			return err
+		}
 	}
 
-	d.SetId("")
-	return diags
+	if d.NewValueKnown("servers") && len(d.Get("servers").([]interface{})) == 0 {
+		return fmt.Errorf("servers: at least one backend server is required")
+	}
+
+	if d.NewValueKnown("ssl_enabled") && d.Get("ssl_enabled").(bool) {
+		if d.NewValueKnown("certificate") && d.Get("certificate").(string) == "" {
+			return fmt.Errorf("certificate: must be set when ssl_enabled is true")
+		}
+		if d.NewValueKnown("private_key") && d.Get("private_key").(string) == "" {
+			return fmt.Errorf("private_key: must be set when ssl_enabled is true")
+		}
+	}
+
+	// Fingerprint and expiry are derived from the certificate locally, so
+	// they are known at plan time.
+	if d.HasChange("certificate") {
+		if !d.NewValueKnown("certificate") {
+			d.SetNewComputed("certificate_fingerprint")
+			d.SetNewComputed("certificate_expiry")
+			return nil
+		}
+		fingerprint, expiry := "", ""
+		if certificate := d.Get("certificate").(string); certificate != "" {
+			var err error
+			fingerprint, expiry, err = certificateInfo(certificate)
+			if err != nil {
+				return fmt.Errorf("certificate: %s", err)
+			}
+		}
+		d.SetNew("certificate_fingerprint", fingerprint)
+		d.SetNew("certificate_expiry", expiry)
+	}
+
+	return nil
 }
 
-// expandServerItems converts a []interface{} -> []ServerItem
-func expandServerItems(list []interface{}) []ServerItem {
-	servers := make([]ServerItem, 0, len(list))
+// expandServerItems converts a []interface{} -> []faxter.ServerItem
+func expandServerItems(list []interface{}) []faxter.ServerItem {
+	servers := make([]faxter.ServerItem, 0, len(list))
 	for _, v := range list {
 		serverMap := v.(map[string]interface{})
-		servers = append(servers, ServerItem{
+		servers = append(servers, faxter.ServerItem{
 			IP:       serverMap["ip"].(string),
 			Port:     serverMap["port"].(int),
 			Endpoint: serverMap["endpoint"].(string),
@@ -370,3 +428,113 @@ func expandServerItems(list []interface{}) []ServerItem {
 	}
 	return servers
 }
+
+// lbMemberParallelism caps concurrent member API calls during an update.
+const lbMemberParallelism = 8
+
+func serverItemKey(item faxter.ServerItem) string {
+	return fmt.Sprintf("%s:%d%s", item.IP, item.Port, item.Endpoint)
+}
+
+// updateLoadBalancerMembers diffs the old and new backend lists and applies
+// the removals, then the additions, through the member endpoints. Calls within
+// each phase run concurrently with bounded parallelism. Removing an already
+// absent member or adding an existing one is not an error, so a partially
+// applied update can simply be retried.
+func updateLoadBalancerMembers(ctx context.Context, c *Client, project, lbName string, oldItems, newItems []faxter.ServerItem) error {
+	oldSet := make(map[string]faxter.ServerItem, len(oldItems))
+	for _, item := range oldItems {
+		oldSet[serverItemKey(item)] = item
+	}
+	newSet := make(map[string]faxter.ServerItem, len(newItems))
+	for _, item := range newItems {
+		newSet[serverItemKey(item)] = item
+	}
+
+	var removals, additions []func(context.Context) error
+	for key, item := range oldSet {
+		if _, ok := newSet[key]; !ok {
+			item := item
+			removals = append(removals, func(ctx context.Context) error {
+				return removeLoadBalancerMember(ctx, c, project, lbName, item)
+			})
+		}
+	}
+	for key, item := range newSet {
+		if _, ok := oldSet[key]; !ok {
+			item := item
+			additions = append(additions, func(ctx context.Context) error {
+				return addLoadBalancerMember(ctx, c, project, lbName, item)
+			})
+		}
+	}
+
+	tflog.Debug(ctx, "Updating load balancer members", map[string]interface{}{
+		"load_balancer": lbName,
+		"removals":      len(removals),
+		"additions":     len(additions),
+	})
+
+	// Removals go first so that re-adding a member with a changed endpoint
+	// does not collide with the old entry on the same ip:port.
+	if err := runBounded(ctx, lbMemberParallelism, removals); err != nil {
+		return err
+	}
+	return runBounded(ctx, lbMemberParallelism, additions)
+}
+
+func addLoadBalancerMember(ctx context.Context, c *Client, project, lbName string, item faxter.ServerItem) error {
+	err := c.api.AddLoadBalancerMember(ctx, project, lbName, item)
+	var apiErr *faxter.Error
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict {
+		// Already a member.
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("adding member %s:%d: %w", item.IP, item.Port, err)
+	}
+	return nil
+}
+
+func removeLoadBalancerMember(ctx context.Context, c *Client, project, lbName string, item faxter.ServerItem) error {
+	err := c.api.RemoveLoadBalancerMember(ctx, project, lbName, item)
+	if err != nil && !faxter.IsNotFound(err) {
+		return fmt.Errorf("removing member %s:%d: %w", item.IP, item.Port, err)
+	}
+	return nil
+}
+
+// runBounded runs tasks with at most limit in flight and returns all of their
+// errors joined together. Tasks not yet started are skipped once ctx is done.
+func runBounded(ctx context.Context, limit int, tasks []func(context.Context) error) error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	sem := make(chan struct{}, limit)
+
+	for _, task := range tasks {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return errors.Join(append(errs, ctx.Err())...)
+		}
+
+		wg.Add(1)
+		go func(task func(context.Context) error) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := task(ctx); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}(task)
+	}
+
+	wg.Wait()
+	return errors.Join(errs...)
+}
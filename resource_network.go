@@ -1,53 +1,45 @@
 package main
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
-	"net/url"
 
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
-// Reflecting the schema from the API:
-// NetworkCreate requires a "subnets" array of objects {name: string, cidr: string}.
-type RouteRule struct {
-	Destination string `json:"destination"`
-	Nexthop     string `json:"nexthop"`
-}
-type SubnetCreateRequest struct {
-	Name         string      `json:"name"`
-	CIDR         string      `json:"cidr"`
-	Gateway      string      `json:"gateway,omitempty"`
-	StaticRoutes []RouteRule `json:"static_routes,omitempty"`
-}
-
-type NetworkCreateRequest struct {
-	Project string                `json:"project,omitempty"`
-	Name    string                `json:"name"`
-	Subnets []SubnetCreateRequest `json:"subnets"`
-}
-
 func resourceNetwork() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceNetworkCreate,
 		ReadContext:   resourceNetworkRead,
 		UpdateContext: resourceNetworkUpdate,
 		DeleteContext: resourceNetworkDelete,
+		CustomizeDiff: customdiff.All(
+			customizeDiffProject,
+			customizeDiffNetwork,
+		),
+		Importer: &schema.ResourceImporter{
+			StateContext: importProjectScoped,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"project": {
 				Type:     schema.TypeString,
 				Optional: true,
-				Default:  "default",
+				Computed: true,
 			},
 			"name": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateName,
+			},
+			"qos_policy": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Name of a faxter_qos_policy applied to all ports on the network.",
 			},
 			"subnets": {
 				Type:     schema.TypeList,
@@ -55,8 +47,9 @@ func resourceNetwork() *schema.Resource {
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"name": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateName,
 						},
 						"cidr": {
 							Type:     schema.TypeString,
@@ -86,36 +79,60 @@ func resourceNetwork() *schema.Resource {
 				},
 				Description: "A list of subnet configurations for this network.",
 			},
+			"subnets_by_name": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Map of subnet name to subnet ID, for wiring into faxter_router.subnets and other subnet-scoped resources.",
+			},
+			"subnet_cidrs_by_name": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Map of subnet name to CIDR as reported by the API.",
+			},
 		},
 	}
 }
 
+// customizeDiffNetwork marks the subnet maps unknown when the subnets
+// change, so that resources referring to a subnet added in the same apply
+// wait for its ID instead of seeing the maps as they were.
+func customizeDiffNetwork(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" || !d.HasChange("subnets") {
+		return nil
+	}
+	if err := d.SetNewComputed("subnets_by_name"); err != nil {
+		return err
+	}
+	return d.SetNewComputed("subnet_cidrs_by_name")
+}
+
 func resourceNetworkCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*Client)
-	var diags diag.Diagnostics
 
 	project := d.Get("project").(string)
 	name := d.Get("name").(string)
 	subnetsIface := d.Get("subnets").([]interface{})
 
 	// Prepare the list of subnets for the request
-	var subnets []SubnetCreateRequest
+	var subnets []faxter.SubnetCreateRequest
 	for _, subnetRaw := range subnetsIface {
 		subnetMap := subnetRaw.(map[string]interface{})
 
-		// Convert "static_routes" from []interface{} to []RouteRule
-		var staticRoutes []RouteRule
+		// Convert "static_routes" from []interface{} to []faxter.RouteRule
+		var staticRoutes []faxter.RouteRule
 		if staticRoutesIface, ok := subnetMap["static_routes"].([]interface{}); ok {
 			for _, routeRaw := range staticRoutesIface {
 				routeMap := routeRaw.(map[string]interface{})
-				staticRoutes = append(staticRoutes, RouteRule{
+				staticRoutes = append(staticRoutes, faxter.RouteRule{
 					Destination: routeMap["destination"].(string),
 					Nexthop:     routeMap["nexthop"].(string),
 				})
 			}
 		}
 
-		subnetReq := SubnetCreateRequest{
+		subnetReq := faxter.SubnetCreateRequest{
 			Name:         subnetMap["name"].(string),
 			CIDR:         subnetMap["cidr"].(string),
 			Gateway:      subnetMap["gateway"].(string), // Optional if not set in schema
@@ -125,37 +142,20 @@ func resourceNetworkCreate(ctx context.Context, d *schema.ResourceData, m interf
 		subnets = append(subnets, subnetReq)
 	}
 
-	reqData := &NetworkCreateRequest{
-		Project: project,
-		Name:    name,
-		Subnets: subnets,
-	}
-
-	bodyBytes, _ := json.Marshal(reqData)
-	req, err := c.newRequest("POST", "/networks/")
-	if err != nil {
-		return diag.FromErr(err)
+	reqData := &faxter.NetworkCreateRequest{
+		Project:   project,
+		Name:      name,
+		Subnets:   subnets,
+		QoSPolicy: d.Get("qos_policy").(string),
 	}
-	req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
 
-	resp, err := c.httpClient.Do(req)
+	resourceResp, err := c.api.CreateNetwork(ctx, reqData)
 	if err != nil {
-		return diag.FromErr(err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return diag.Errorf("Failed to create network: %s", resp.Status)
-	}
-
-	var resourceResp ResourceResponse
-	err = json.NewDecoder(resp.Body).Decode(&resourceResp)
-	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiag("Failed to create network", err)
 	}
 
 	d.SetId(resourceResp.Name)
-	return diags
+	return resourceNetworkRead(ctx, d, m)
 }
 
 func resourceNetworkRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -164,102 +164,100 @@ func resourceNetworkRead(ctx context.Context, d *schema.ResourceData, m interfac
 
 	name := d.Id()
 	project := d.Get("project").(string)
-	path := fmt.Sprintf("/networks/%s?project_name=%s", url.PathEscape(name), url.QueryEscape(project))
-	req, err := c.newRequest("GET", path)
-	if err != nil {
-		return diag.FromErr(err)
-	}
 
-	resp, err := c.httpClient.Do(req)
+	network, err := getNetwork(ctx, c, project, name)
+	if errors.Is(err, errNotFound) {
+		d.SetId("")
+		return diags
+	}
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiag("Failed to read network", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusNotFound {
-		d.SetId("")
-		return diags
+	// The configured subnets are left as-is; the computed maps expose what the
+	// API actually created so other resources can reference subnet IDs.
+	subnetIDs := make(map[string]interface{}, len(network.Properties.Subnets))
+	subnetCIDRs := make(map[string]interface{}, len(network.Properties.Subnets))
+	for _, subnet := range network.Properties.Subnets {
+		subnetIDs[subnet.Name] = subnet.ID
+		subnetCIDRs[subnet.Name] = subnet.CIDR
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return diag.Errorf("Failed to read network: %s", resp.Status)
+	if err := d.Set("subnets_by_name", subnetIDs); err != nil {
+		return diag.Errorf("Error setting subnets_by_name: %s", err)
 	}
 
-	// If the GET response returns info about subnets, parse them here and update state.
-	// The schema suggests it might return ResourceResponse, which may not have detailed subnets info.
-	// Without subnet details in the response, we cannot reliably update subnets.
-	// Assuming we only confirm existence for now.
+	if err := d.Set("subnet_cidrs_by_name", subnetCIDRs); err != nil {
+		return diag.Errorf("Error setting subnet_cidrs_by_name: %s", err)
+	}
 
 	return diags
 }
 
+// getNetwork fetches a network and its subnets. A missing network is reported
+// as an error wrapping errNotFound.
+func getNetwork(ctx context.Context, c *Client, project, name string) (*faxter.NetworkResponse, error) {
+	network, err := c.api.GetNetwork(ctx, project, name)
+	if faxter.IsNotFound(err) {
+		return nil, fmt.Errorf("network '%s' %w", name, errNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return network, nil
+}
+
 func resourceNetworkUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*Client)
-	var diags diag.Diagnostics
 
 	oldName := d.Id()
 	project := d.Get("project").(string)
 	newName := d.Get("name").(string)
 	subnetsIface := d.Get("subnets").([]interface{})
 
-	var subnets []SubnetCreateRequest
+	var subnets []faxter.SubnetCreateRequest
 	for _, subnetRaw := range subnetsIface {
 		subnetMap := subnetRaw.(map[string]interface{})
-		subnets = append(subnets, SubnetCreateRequest{
+		subnets = append(subnets, faxter.SubnetCreateRequest{
 			Name: subnetMap["name"].(string),
 			CIDR: subnetMap["cidr"].(string),
 		})
 	}
 
-	updateBody := &NetworkCreateRequest{
-		Project: project,
-		Name:    newName,
-		Subnets: subnets,
+	updateBody := &faxter.NetworkCreateRequest{
+		Project:   project,
+		Name:      newName,
+		Subnets:   subnets,
+		QoSPolicy: d.Get("qos_policy").(string),
 	}
 
-	bodyBytes, _ := json.Marshal(updateBody)
-	path := fmt.Sprintf("/networks/%s?project_name=%s", url.PathEscape(oldName), url.QueryEscape(project))
-	req, err := c.newRequest("PUT", path)
-	if err != nil {
-		return diag.FromErr(err)
+	err := c.api.UpdateNetwork(ctx, project, oldName, updateBody)
+	if faxter.IsNotFound(err) {
+		return resourceGone(d, "network")
 	}
-
-	req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
-	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return diag.FromErr(err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return diag.Errorf("Failed to update network: %s", resp.Status)
+		return apiErrorDiag("Failed to update network", err)
 	}
 
 	// If the network name changes are allowed and accepted, update ID.
-	d.SetId(newName)
-	return diags
+	if renameDiags := applyRename(ctx, c, d, "network", func(name string) string {
+		return faxter.ObjectPath("networks", project, name)
+	}, oldName, newName); renameDiags.HasError() {
+		return renameDiags
+	}
+	return resourceNetworkRead(ctx, d, m)
 }
 
 func resourceNetworkDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*Client)
 	var diags diag.Diagnostics
 
-	name := d.Id()
-	project := d.Get("project").(string)
-	path := fmt.Sprintf("/networks/%s?project_name=%s", url.PathEscape(name), url.QueryEscape(project))
-	req, err := c.newRequest("DELETE", path)
-	if err != nil {
-		return diag.FromErr(err)
+	err := c.api.DeleteNetwork(ctx, d.Get("project").(string), d.Id())
+	if faxter.IsNotFound(err) {
+		return resourceGone(d, "network")
 	}
-
-	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return diag.FromErr(err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return diag.Errorf("Failed to delete network: %s", resp.Status)
+		return apiErrorDiag("Failed to delete network", err)
 	}
 
 	d.SetId("")
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/structure"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceObjectStorageBucketPolicy() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceObjectStorageBucketPolicyPut,
+		ReadContext:   resourceObjectStorageBucketPolicyRead,
+		UpdateContext: resourceObjectStorageBucketPolicyPut,
+		DeleteContext: resourceObjectStorageBucketPolicyDelete,
+		CustomizeDiff: customizeDiffProject,
+
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "Project that owns the bucket.",
+			},
+			"bucket": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Name of the object storage bucket the policy applies to.",
+			},
+			"policy": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateFunc:     validation.StringIsJSON,
+				DiffSuppressFunc: structure.SuppressJsonDiff,
+				StateFunc: func(v interface{}) string {
+					normalized, _ := structure.NormalizeJsonString(v)
+					return normalized
+				},
+				Description: "Bucket policy document as JSON, e.g. built with jsonencode(). Formatting and key order differences are ignored.",
+			},
+		},
+	}
+}
+
+func bucketPolicyPath(project, bucket string) string {
+	return fmt.Sprintf("/object_storage/buckets/%s/policy?project_name=%s", url.PathEscape(bucket), url.QueryEscape(project))
+}
+
+// resourceObjectStorageBucketPolicyPut creates or replaces the policy; the API treats both
+// the same way since a bucket has at most one policy.
+func resourceObjectStorageBucketPolicyPut(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	project := d.Get("project").(string)
+	bucket := d.Get("bucket").(string)
+
+	reqData := &faxter.BucketPolicyRequest{
+		Project: project,
+		Policy:  json.RawMessage(d.Get("policy").(string)),
+	}
+
+	if err := c.api.PutBucketPolicy(ctx, project, bucket, reqData); err != nil {
+		return apiErrorDiag("Failed to set bucket policy", err)
+	}
+
+	d.SetId(bucket)
+	return resourceObjectStorageBucketPolicyRead(ctx, d, m)
+}
+
+func resourceObjectStorageBucketPolicyRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+	var diags diag.Diagnostics
+
+	policyResp, err := c.api.GetBucketPolicy(ctx, d.Get("project").(string), d.Id())
+	if faxter.IsNotFound(err) {
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return apiErrorDiag("Failed to read bucket policy", err)
+	}
+
+	policy, err := structure.NormalizeJsonString(string(policyResp.Policy))
+	if err != nil {
+		return diag.Errorf("Error normalizing bucket policy returned by the API: %s", err)
+	}
+
+	if err := d.Set("bucket", d.Id()); err != nil {
+		return diag.Errorf("Error setting bucket: %s", err)
+	}
+
+	if err := d.Set("policy", policy); err != nil {
+		return diag.Errorf("Error setting policy: %s", err)
+	}
+
+	return diags
+}
+
+func resourceObjectStorageBucketPolicyDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+	var diags diag.Diagnostics
+
+	err := c.api.DeleteBucketPolicy(ctx, d.Get("project").(string), d.Id())
+	if err != nil && !faxter.IsNotFound(err) {
+		return apiErrorDiag("Failed to delete bucket policy", err)
+	}
+
+	d.SetId("")
+	return diags
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// placementPolicyCapability is reported by APIs that support placement
+// policies.
+const placementPolicyCapability = "placement_policies"
+
+// resourcePlacementPolicy manages a placement policy: whether the servers
+// that name it in placement_policy are spread apart or packed together.
+// Stating the intent once lets every server of a tier share it.
+func resourcePlacementPolicy() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourcePlacementPolicyCreate,
+		ReadContext:   resourcePlacementPolicyRead,
+		UpdateContext: resourcePlacementPolicyUpdate,
+		DeleteContext: resourcePlacementPolicyDelete,
+		CustomizeDiff: customdiff.All(
+			customizeDiffProject,
+			customizeDiffPlacementPolicy,
+		),
+		Importer: &schema.ResourceImporter{
+			StateContext: importProjectScoped,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateName,
+			},
+			"strategy": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice([]string{"spread", "pack"}, false),
+				Description:  "\"spread\" schedules the servers apart from each other, for availability; \"pack\" schedules them together, for locality.",
+			},
+			"scope": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "host",
+				ValidateFunc: validation.StringInSlice([]string{"host", "availability_zone"}, false),
+				Description:  "What a spread policy keeps the servers apart across: \"host\" or \"availability_zone\".",
+			},
+			"max_per_host": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Most servers of the policy on one host. Zero, the default, sets no limit.",
+			},
+			"servers": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Servers placed under the policy.",
+			},
+		},
+	}
+}
+
+// customizeDiffPlacementPolicy fails the plan when the API doesn't support
+// placement policies or the rules contradict each other.
+func customizeDiffPlacementPolicy(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if err := m.(*Client).requireCapability(placementPolicyCapability, "faxter_placement_policy"); err != nil {
+		return err
+	}
+	// Packing across availability zones isn't a placement the scheduler can
+	// make.
+	if d.Get("strategy").(string) == "pack" && d.Get("scope").(string) == "availability_zone" {
+		return fmt.Errorf("scope: a pack policy can only be scoped to a host")
+	}
+	return nil
+}
+
+func expandPlacementPolicy(d *schema.ResourceData) *faxter.PlacementPolicyRequest {
+	return &faxter.PlacementPolicyRequest{
+		Project:    d.Get("project").(string),
+		Name:       d.Get("name").(string),
+		Strategy:   d.Get("strategy").(string),
+		Scope:      d.Get("scope").(string),
+		MaxPerHost: d.Get("max_per_host").(int),
+	}
+}
+
+func resourcePlacementPolicyCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	reqData := expandPlacementPolicy(d)
+	if err := c.api.CreatePlacementPolicy(ctx, reqData); err != nil {
+		return apiErrorDiag("Failed to create placement policy", err)
+	}
+
+	d.SetId(reqData.Name)
+	return resourcePlacementPolicyRead(ctx, d, m)
+}
+
+func resourcePlacementPolicyRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+	var diags diag.Diagnostics
+
+	policy, err := c.api.GetPlacementPolicy(ctx, d.Get("project").(string), d.Id())
+	if faxter.IsNotFound(err) {
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return apiErrorDiag("Failed to read placement policy", err)
+	}
+
+	d.Set("name", d.Id())
+	d.Set("strategy", policy.Strategy)
+	if policy.Scope != "" {
+		d.Set("scope", policy.Scope)
+	}
+	d.Set("max_per_host", policy.MaxPerHost)
+	if err := d.Set("servers", policy.Servers); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
+
+func resourcePlacementPolicyUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	err := c.api.UpdatePlacementPolicy(ctx, d.Get("project").(string), d.Id(), expandPlacementPolicy(d))
+	if faxter.IsNotFound(err) {
+		return resourceGone(d, "placement policy")
+	}
+	if err != nil {
+		return apiErrorDiag("Failed to update placement policy", err)
+	}
+
+	return resourcePlacementPolicyRead(ctx, d, m)
+}
+
+func resourcePlacementPolicyDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+	var diags diag.Diagnostics
+
+	err := c.api.DeletePlacementPolicy(ctx, d.Get("project").(string), d.Id())
+	if faxter.IsNotFound(err) {
+		return resourceGone(d, "placement policy")
+	}
+	if err != nil {
+		return apiErrorDiag("Failed to delete placement policy", err)
+	}
+
+	d.SetId("")
+	return diags
+}
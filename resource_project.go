@@ -2,29 +2,31 @@ package main
 
 import (
   "context"
-  "encoding/json"
   "fmt"
-  "io"
-  "bytes"
+  "net/url"
+  "strings"
+  "github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+  "github.com/hashicorp/terraform-plugin-log/tflog"
   "github.com/hashicorp/terraform-plugin-sdk/v2/diag"
   "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
-type ProjectCreateRequest struct {
-  Name string `json:"name"`
-}
-
 func resourceProject() *schema.Resource {
   return &schema.Resource{
     CreateContext: resourceProjectCreate,
     ReadContext:   resourceProjectRead,
 	UpdateContext: resourceProjectUpdate,
     DeleteContext: resourceProjectDelete,
+    CustomizeDiff: customizeDiffProjectRename,
+    Importer: &schema.ResourceImporter{
+      StateContext: schema.ImportStatePassthroughContext,
+    },
 
     Schema: map[string]*schema.Schema{
       "name": {
-        Type:     schema.TypeString,
-        Required: true,
+        Type:         schema.TypeString,
+        Required:     true,
+        ValidateFunc: validateName,
       },
     },
   }
@@ -37,22 +39,8 @@ func resourceProjectCreate(ctx context.Context, d *schema.ResourceData, m interf
   name := d.Get("name").(string)
 
   // Create project
-  bodyData := &ProjectCreateRequest{Name: name}
-  bodyBytes, _ := json.Marshal(bodyData)
-  req, err := c.newRequest("POST", "/projects")
-  if err != nil {
-    return diag.FromErr(err)
-  }
-  req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
-
-  resp, err := c.httpClient.Do(req)
-  if err != nil {
-    return diag.FromErr(err)
-  }
-  defer resp.Body.Close()
-
-  if resp.StatusCode != 200 {
-    return diag.Errorf("Failed to create project: %s", resp.Status)
+  if err := c.api.CreateProject(ctx, name); err != nil {
+    return apiErrorDiag("Failed to create project", err)
   }
 
   // On success, set the ID to project name (as unique ID)
@@ -67,27 +55,16 @@ func resourceProjectRead(ctx context.Context, d *schema.ResourceData, m interfac
 
   name := d.Id()
 
-  req, err := c.newRequest("GET", fmt.Sprintf("/projects/%s", name))
-  if err != nil {
-    return diag.FromErr(err)
-  }
-
-  resp, err := c.httpClient.Do(req)
+  exists, err := c.api.ProjectExists(ctx, name)
   if err != nil {
-    return diag.FromErr(err)
+    return apiErrorDiag("Failed to read project", err)
   }
-  defer resp.Body.Close()
-
-  if resp.StatusCode == 404 {
+  if !exists {
     // If project not found, remove it from state
     d.SetId("")
     return diags
   }
 
-  if resp.StatusCode != 200 {
-    return diag.Errorf("Failed to read project: %s", resp.Status)
-  }
-
   // If needed, parse project response to update state
   // Currently we only store `name`
   // If project exists, ensure `name` matches
@@ -98,42 +75,22 @@ func resourceProjectRead(ctx context.Context, d *schema.ResourceData, m interfac
 
 func resourceProjectUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*Client)
-	var diags diag.Diagnostics
-  
+
+	// Renames only reach this point when allow_project_rename is set;
+	// otherwise customizeDiffProjectRename forces a replacement. The API
+	// takes the old name in the URL and the new name in the PUT request body.
 	oldName, newName := d.GetChange("name")
-	// The API uses the project_name path param. If the name changed, we must handle how the API treats renames.
-	// If renaming is allowed by the API, we would:
-	// 1) Use the old name in the URL.
-	// 2) Send the new name in the PUT request body.
-	// If renaming is not allowed, we might have to recreate the resource.
-	// Assuming renaming is allowed for demonstration:
-  
-	projectName := oldName.(string)
-	updateBody := &ProjectCreateRequest{
-	  Name: newName.(string),
-	}
-  
-	bodyBytes, _ := json.Marshal(updateBody)
-	req, err := c.newRequest("PUT", fmt.Sprintf("/projects/%s", projectName))
-	if err != nil {
-	  return diag.FromErr(err)
+	err := c.api.RenameProject(ctx, oldName.(string), newName.(string))
+	if faxter.IsNotFound(err) {
+		return resourceGone(d, "project")
 	}
-	req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
-  
-	resp, err := c.httpClient.Do(req)
 	if err != nil {
-	  return diag.FromErr(err)
-	}
-	defer resp.Body.Close()
-  
-	if resp.StatusCode != 200 {
-	  return diag.Errorf("Failed to update project: %s", resp.Status)
+		return apiErrorDiag("Failed to update project", err)
 	}
-  
-	// If successful, set ID to the new name.
-	d.SetId(newName.(string))
-  
-	return diags
+
+	return applyRename(ctx, c, d, "project", func(name string) string {
+		return "/projects/" + url.PathEscape(name)
+	}, oldName.(string), newName.(string))
 }
 
 func resourceProjectDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -142,23 +99,107 @@ func resourceProjectDelete(ctx context.Context, d *schema.ResourceData, m interf
 
   name := d.Id()
 
-  req, err := c.newRequest("DELETE", fmt.Sprintf("/projects/%s", name))
+  err := c.api.DeleteProject(ctx, name)
+  if faxter.IsNotFound(err) {
+    return resourceGone(d, "project")
+  }
   if err != nil {
-    return diag.FromErr(err)
+    return apiErrorDiag("Failed to delete project", err)
+  }
+
+  // Remove from state
+  d.SetId("")
+
+  return diags
+}
+
+// customizeDiffProjectRename replaces the project on a name change unless the
+// provider has been told the API supports renaming projects in place.
+func customizeDiffProjectRename(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+  c := m.(*Client)
+  if d.Id() == "" || !d.HasChange("name") || c.allowProjectRename {
+    return nil
   }
 
-  resp, err := c.httpClient.Do(req)
+  tflog.Warn(ctx, "Project rename requires replacement; set allow_project_rename in the provider to rename in place", map[string]interface{}{
+    "project": d.Id(),
+  })
+  return d.ForceNew("name")
+}
+
+// projectExists reports whether the named project can be found via the API.
+func projectExists(ctx context.Context, c *Client, name string) (bool, error) {
+  exists, err := c.api.ProjectExists(ctx, name)
   if err != nil {
-    return diag.FromErr(err)
+    return false, fmt.Errorf("failed to look up project '%s': %w", name, err)
   }
-  defer resp.Body.Close()
+  return exists, nil
+}
+
+// customizeDiffProject is shared by project-scoped resources. A resource that
+// leaves project unset inherits the provider's project (a faxter_project's id
+// is its name, so it can also be referenced directly). When the provider is
+// constrained to its project, any other project fails the plan; when
+// validate_projects is on, a project that does not exist fails the plan too,
+// instead of surfacing a 404 during apply.
+func customizeDiffProject(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+  c := m.(*Client)
 
-  if resp.StatusCode != 200 {
-    return diag.Errorf("Failed to delete project: %s", resp.Status)
+  if config := d.GetRawConfig(); d.Id() == "" && !config.IsNull() && config.GetAttr("project").IsNull() {
+    if err := d.SetNew("project", c.defaultProject); err != nil {
+      return err
+    }
   }
 
-  // Remove from state
-  d.SetId("")
+  // Skip values that are not known until apply (e.g. a faxter_project
+  // created in the same run).
+  if !d.NewValueKnown("project") {
+    return nil
+  }
+  project := d.Get("project").(string)
 
-  return diags
-}
\ No newline at end of file
+  if c.enforceProject && project != c.defaultProject {
+    return fmt.Errorf("project '%s' is not allowed: the provider is configured with enforce_project and only manages resources in project '%s'", project, c.defaultProject)
+  }
+
+  if !c.validateProjects || project == "" {
+    return nil
+  }
+  // Only check new resources or a changed reference.
+  if d.Id() != "" && !d.HasChange("project") {
+    return nil
+  }
+
+  exists, err := projectExists(ctx, c, project)
+  if err != nil {
+    return err
+  }
+  if !exists {
+    return fmt.Errorf("project '%s' does not exist: check the spelling, or manage it with a faxter_project resource and reference its name", project)
+  }
+
+  return nil
+}
+
+// importProjectScoped imports a project-scoped resource identified by name.
+// It accepts "<project>/<name>", or just "<name>" for the provider's project.
+func importProjectScoped(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+  c := m.(*Client)
+
+  project, name := c.defaultProject, d.Id()
+  if parts := strings.SplitN(d.Id(), "/", 2); len(parts) == 2 {
+    project, name = parts[0], parts[1]
+  }
+  if name == "" {
+    return nil, fmt.Errorf("unexpected import ID %q, expected <project>/<name> or <name>", d.Id())
+  }
+
+  d.SetId(name)
+  if err := d.Set("project", project); err != nil {
+    return nil, err
+  }
+  if err := d.Set("name", name); err != nil {
+    return nil, err
+  }
+  return []*schema.ResourceData{d}, nil
+}
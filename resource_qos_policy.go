@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// resourceQoSPolicy manages a named QoS policy that networks (qos_policy) and
+// server network attachments (network_qos) refer to.
+func resourceQoSPolicy() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceQoSPolicyCreate,
+		ReadContext:   resourceQoSPolicyRead,
+		UpdateContext: resourceQoSPolicyUpdate,
+		DeleteContext: resourceQoSPolicyDelete,
+		CustomizeDiff: customizeDiffProject,
+		Importer: &schema.ResourceImporter{
+			StateContext: importProjectScoped,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateName,
+			},
+			"max_bandwidth": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				AtLeastOneOf: []string{"max_bandwidth", "dscp_mark"},
+				Description:  "Maximum bandwidth in Mbit/s.",
+			},
+			"max_burst": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				RequiredWith: []string{"max_bandwidth"},
+				Description:  "Burst size in kbit allowed above max_bandwidth.",
+			},
+			"dscp_mark": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntBetween(0, 63),
+				Description:  "DSCP value set on outgoing packets.",
+			},
+		},
+	}
+}
+
+func expandQoSPolicy(d *schema.ResourceData) *faxter.QoSPolicyRequest {
+	reqData := &faxter.QoSPolicyRequest{
+		Project:          d.Get("project").(string),
+		Name:             d.Get("name").(string),
+		MaxBandwidthMbps: d.Get("max_bandwidth").(int),
+		MaxBurstKbits:    d.Get("max_burst").(int),
+	}
+	// 0 is a valid DSCP value, so presence is taken from the configuration.
+	if v, ok := d.GetOkExists("dscp_mark"); ok {
+		mark := v.(int)
+		reqData.DSCPMark = &mark
+	}
+	return reqData
+}
+
+func resourceQoSPolicyCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	reqData := expandQoSPolicy(d)
+	if err := c.api.CreateQoSPolicy(ctx, reqData); err != nil {
+		return apiErrorDiag("Failed to create QoS policy", err)
+	}
+
+	d.SetId(reqData.Name)
+	return resourceQoSPolicyRead(ctx, d, m)
+}
+
+func resourceQoSPolicyRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+	var diags diag.Diagnostics
+
+	policy, err := c.api.GetQoSPolicy(ctx, d.Get("project").(string), d.Id())
+	if faxter.IsNotFound(err) {
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return apiErrorDiag("Failed to read QoS policy", err)
+	}
+
+	d.Set("name", d.Id())
+	d.Set("max_bandwidth", policy.MaxBandwidthMbps)
+	d.Set("max_burst", policy.MaxBurstKbits)
+	if policy.DSCPMark != nil {
+		d.Set("dscp_mark", *policy.DSCPMark)
+	} else {
+		d.Set("dscp_mark", nil)
+	}
+
+	return diags
+}
+
+func resourceQoSPolicyUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	err := c.api.UpdateQoSPolicy(ctx, d.Get("project").(string), d.Id(), expandQoSPolicy(d))
+	if faxter.IsNotFound(err) {
+		return resourceGone(d, "QoS policy")
+	}
+	if err != nil {
+		return apiErrorDiag("Failed to update QoS policy", err)
+	}
+
+	return resourceQoSPolicyRead(ctx, d, m)
+}
+
+func resourceQoSPolicyDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+	var diags diag.Diagnostics
+
+	err := c.api.DeleteQoSPolicy(ctx, d.Get("project").(string), d.Id())
+	if faxter.IsNotFound(err) {
+		return resourceGone(d, "QoS policy")
+	}
+	if err != nil {
+		return apiErrorDiag("Failed to delete QoS policy", err)
+	}
+
+	d.SetId("")
+	return diags
+}
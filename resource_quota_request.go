@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// resourceQuotaRequest files a quota increase for approval. A request cannot
+// be edited once filed, so every argument forces a new request.
+func resourceQuotaRequest() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceQuotaRequestCreate,
+		ReadContext:   resourceQuotaRequestRead,
+		DeleteContext: resourceQuotaRequestDelete,
+		CustomizeDiff: customizeDiffProject,
+
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"resource_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				Description:  "Quota being raised, e.g. cores, ram_gb, floating_ips or volumes_gb.",
+			},
+			"amount": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "New quota limit requested.",
+			},
+			"justification": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				Description:  "Reason for the increase, shown to the approver.",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Approval status, e.g. pending, approved or rejected.",
+			},
+			"review_comment": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Comment left by the approver, if any.",
+			},
+		},
+	}
+}
+
+func resourceQuotaRequestCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	reqData := &faxter.QuotaRequestRequest{
+		Project:       d.Get("project").(string),
+		ResourceType:  d.Get("resource_type").(string),
+		Amount:        d.Get("amount").(int),
+		Justification: d.Get("justification").(string),
+	}
+
+	quotaReq, err := c.api.CreateQuotaRequest(ctx, reqData)
+	if err != nil {
+		return apiErrorDiag("Failed to create quota request", err)
+	}
+	if quotaReq.ID == "" {
+		return diag.Errorf("No quota request ID returned in create response")
+	}
+
+	d.SetId(quotaReq.ID)
+	return resourceQuotaRequestRead(ctx, d, m)
+}
+
+func resourceQuotaRequestRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+	var diags diag.Diagnostics
+
+	quotaReq, err := c.api.GetQuotaRequest(ctx, d.Get("project").(string), d.Id())
+	if faxter.IsNotFound(err) {
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return apiErrorDiag("Failed to read quota request", err)
+	}
+
+	d.Set("resource_type", quotaReq.ResourceType)
+	d.Set("amount", quotaReq.Amount)
+	d.Set("justification", quotaReq.Justification)
+	d.Set("status", quotaReq.Status)
+	d.Set("review_comment", quotaReq.ReviewComment)
+
+	return diags
+}
+
+// resourceQuotaRequestDelete withdraws a pending request. A request that has
+// already been decided cannot be withdrawn; it is only dropped from state and
+// the granted quota stays in place.
+func resourceQuotaRequestDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+	var diags diag.Diagnostics
+
+	err := c.api.WithdrawQuotaRequest(ctx, d.Get("project").(string), d.Id())
+	var apiErr *faxter.Error
+	switch {
+	case err == nil:
+	case faxter.IsNotFound(err):
+		return resourceGone(d, "quota request")
+	case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict:
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Quota request %q was already %s", d.Id(), d.Get("status").(string)),
+			Detail:   "Decided quota requests cannot be withdrawn. The request has been removed from state; the project's quota is unchanged.",
+		})
+	default:
+		return apiErrorDiag("Failed to delete quota request", err)
+	}
+
+	d.SetId("")
+	return diags
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// lockCollections maps resource_type to the API collection holding it.
+var lockCollections = map[string]string{
+	"server": "servers",
+	"volume": "volumes",
+}
+
+// resourceResourceLock locks a server or volume managed elsewhere, e.g. in
+// another configuration, so it cannot be deleted from the console.
+func resourceResourceLock() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceResourceLockCreate,
+		ReadContext:   resourceResourceLockRead,
+		DeleteContext: resourceResourceLockDelete,
+		CustomizeDiff: customizeDiffProject,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceResourceLockImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"resource_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"server", "volume"}, false),
+				Description:  "Kind of object to lock: server or volume.",
+			},
+			"resource_name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Name of the server or volume to lock.",
+			},
+			"reason": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "Why the object is locked, shown to anyone trying to delete it.",
+			},
+		},
+	}
+}
+
+func resourceResourceLockCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	if err := c.requireCapability(lockCapability, "faxter_resource_lock"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	kind := d.Get("resource_type").(string)
+	name := d.Get("resource_name").(string)
+	reason := d.Get("reason").(string)
+	if reason == "" {
+		reason = "managed by Terraform"
+	}
+
+	if err := c.api.Lock(ctx, lockCollections[kind], d.Get("project").(string), name, reason); err != nil {
+		return apiErrorDiag(fmt.Sprintf("Failed to lock %s '%s'", kind, name), err)
+	}
+	d.SetId(kind + "/" + name)
+
+	return resourceResourceLockRead(ctx, d, m)
+}
+
+func resourceResourceLockRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+	var diags diag.Diagnostics
+
+	kind := d.Get("resource_type").(string)
+	lock, err := c.api.GetLock(ctx, lockCollections[kind], d.Get("project").(string), d.Get("resource_name").(string))
+	if faxter.IsNotFound(err) || (err == nil && !lock.Locked) {
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return apiErrorDiag(fmt.Sprintf("Failed to read %s lock", kind), err)
+	}
+
+	d.Set("reason", lock.Reason)
+	return diags
+}
+
+func resourceResourceLockDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+	var diags diag.Diagnostics
+
+	kind := d.Get("resource_type").(string)
+	err := c.api.Unlock(ctx, lockCollections[kind], d.Get("project").(string), d.Get("resource_name").(string))
+	if faxter.IsNotFound(err) {
+		return resourceGone(d, kind+" lock")
+	}
+	if err != nil {
+		return apiErrorDiag("Failed to unlock "+kind, err)
+	}
+
+	d.SetId("")
+	return diags
+}
+
+// resourceResourceLockImport accepts "<project>/<resource_type>/<name>", or
+// "<resource_type>/<name>" for an object in the provider's project.
+func resourceResourceLockImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	c := m.(*Client)
+
+	parts := strings.Split(d.Id(), "/")
+	if len(parts) == 2 {
+		parts = append([]string{c.defaultProject}, parts...)
+	}
+	if len(parts) != 3 || lockCollections[parts[1]] == "" || parts[2] == "" {
+		return nil, fmt.Errorf("unexpected import ID %q, expected <project>/<server|volume>/<name> or <server|volume>/<name>", d.Id())
+	}
+
+	d.SetId(parts[1] + "/" + parts[2])
+	d.Set("project", parts[0])
+	d.Set("resource_type", parts[1])
+	d.Set("resource_name", parts[2])
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceReverseDNS() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceReverseDNSCreate,
+		ReadContext:   resourceReverseDNSRead,
+		UpdateContext: resourceReverseDNSUpdate,
+		DeleteContext: resourceReverseDNSDelete,
+		CustomizeDiff: customizeDiffProject,
+
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "Project that owns the floating IP.",
+			},
+			"floating_ip": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsIPAddress,
+				Description:  "Floating IP address whose reverse DNS record is managed.",
+			},
+			"ptr_record": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringMatch(ptrRecordRegexp, "must be a fully qualified hostname such as mail.example.com"),
+				// The API may return the record with or without a trailing dot.
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return strings.TrimSuffix(old, ".") == strings.TrimSuffix(new, ".")
+				},
+				Description: "Hostname the floating IP resolves to in reverse lookups.",
+			},
+		},
+	}
+}
+
+func resourceReverseDNSCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	reqData := &faxter.ReverseDNSRequest{
+		Project:    d.Get("project").(string),
+		FloatingIP: d.Get("floating_ip").(string),
+		PTRRecord:  d.Get("ptr_record").(string),
+	}
+
+	if err := c.api.CreateReverseDNS(ctx, reqData); err != nil {
+		return apiErrorDiag("Failed to create reverse DNS record", err)
+	}
+
+	// A floating IP has exactly one PTR record, so the IP identifies it.
+	d.SetId(reqData.FloatingIP)
+	return resourceReverseDNSRead(ctx, d, m)
+}
+
+func resourceReverseDNSRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+	var diags diag.Diagnostics
+
+	rdns, err := c.api.GetReverseDNS(ctx, d.Get("project").(string), d.Id())
+	if faxter.IsNotFound(err) {
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return apiErrorDiag("Failed to read reverse DNS record", err)
+	}
+
+	if err := d.Set("floating_ip", d.Id()); err != nil {
+		return diag.Errorf("Error setting floating_ip: %s", err)
+	}
+
+	if err := d.Set("ptr_record", rdns.PTRRecord); err != nil {
+		return diag.Errorf("Error setting ptr_record: %s", err)
+	}
+
+	return diags
+}
+
+func resourceReverseDNSUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	project := d.Get("project").(string)
+	reqData := &faxter.ReverseDNSRequest{
+		Project:    project,
+		FloatingIP: d.Id(),
+		PTRRecord:  d.Get("ptr_record").(string),
+	}
+
+	err := c.api.UpdateReverseDNS(ctx, project, d.Id(), reqData)
+	if faxter.IsNotFound(err) {
+		return resourceGone(d, "reverse DNS record")
+	}
+	if err != nil {
+		return apiErrorDiag("Failed to update reverse DNS record", err)
+	}
+
+	return resourceReverseDNSRead(ctx, d, m)
+}
+
+func resourceReverseDNSDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+	var diags diag.Diagnostics
+
+	err := c.api.DeleteReverseDNS(ctx, d.Get("project").(string), d.Id())
+	if faxter.IsNotFound(err) {
+		return resourceGone(d, "reverse DNS record")
+	}
+	if err != nil {
+		return apiErrorDiag("Failed to delete reverse DNS record", err)
+	}
+
+	d.SetId("")
+	return diags
+}
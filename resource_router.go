@@ -2,40 +2,34 @@ package main
 
 import (
   "context"
-  "encoding/json"
   "fmt"
-  "io"
-  "bytes"
-  "net/http"
-  "net/url"
 
+  "github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
   "github.com/hashicorp/terraform-plugin-sdk/v2/diag"
   "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
-type RouterCreateRequest struct {
-  Project         string   `json:"project,omitempty"`
-  Name            string   `json:"name"`
-  ConnectExternal bool     `json:"connect_external,omitempty"`
-  Subnets         []string `json:"subnets"`
-}
-
 func resourceRouter() *schema.Resource {
   return &schema.Resource{
     CreateContext: resourceRouterCreate,
     ReadContext:   resourceRouterRead,
     UpdateContext: resourceRouterUpdate,
     DeleteContext: resourceRouterDelete,
+    CustomizeDiff: customizeDiffProject,
+    Importer: &schema.ResourceImporter{
+      StateContext: importProjectScoped,
+    },
 
     Schema: map[string]*schema.Schema{
       "project": {
         Type:     schema.TypeString,
         Optional: true,
-        Default:  "default",
+        Computed: true,
       },
       "name": {
-        Type:     schema.TypeString,
-        Required: true,
+        Type:         schema.TypeString,
+        Required:     true,
+        ValidateFunc: validateName,
       },
       "connect_external": {
         Type:     schema.TypeBool,
@@ -43,9 +37,17 @@ func resourceRouter() *schema.Resource {
         Default:  true,
       },
       "subnets": {
-        Type:     schema.TypeList,
-        Required: true,
-        Elem:     &schema.Schema{Type: schema.TypeString},
+        Type:         schema.TypeList,
+        Optional:     true,
+        Elem:         &schema.Schema{Type: schema.TypeString},
+        AtLeastOneOf: []string{"subnets", "networks"},
+      },
+      "networks": {
+        Type:         schema.TypeList,
+        Optional:     true,
+        Elem:         &schema.Schema{Type: schema.TypeString},
+        AtLeastOneOf: []string{"subnets", "networks"},
+        Description:  "Networks whose subnets are all attached to the router. Subnets are looked up at apply time and combined with any listed in subnets.",
       },
     },
   }
@@ -55,38 +57,22 @@ func resourceRouterCreate(ctx context.Context, d *schema.ResourceData, m interfa
   c := m.(*Client)
   var diags diag.Diagnostics
 
-  reqData := &RouterCreateRequest{
-    Project:         d.Get("project").(string),
-    Name:            d.Get("name").(string),
-    ConnectExternal: d.Get("connect_external").(bool),
-  }
-
-  subnets := d.Get("subnets").([]interface{})
-  for _, s := range subnets {
-    reqData.Subnets = append(reqData.Subnets, s.(string))
-  }
-
-  bodyBytes, _ := json.Marshal(reqData)
-  req, err := c.newRequest("POST", "/routers/")
-  if err != nil {
-    return diag.FromErr(err)
-  }
-  req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
-
-  resp, err := c.httpClient.Do(req)
+  project := d.Get("project").(string)
+  subnets, err := resolveRouterSubnets(ctx, c, project, d)
   if err != nil {
     return diag.FromErr(err)
   }
-  defer resp.Body.Close()
 
-  if resp.StatusCode != http.StatusOK {
-    return diag.Errorf("Failed to create router: %s", resp.Status)
+  reqData := &faxter.RouterCreateRequest{
+    Project:         project,
+    Name:            d.Get("name").(string),
+    ConnectExternal: d.Get("connect_external").(bool),
+    Subnets:         subnets,
   }
 
-  var resourceResp ResourceResponse
-  err = json.NewDecoder(resp.Body).Decode(&resourceResp)
+  resourceResp, err := c.api.CreateRouter(ctx, reqData)
   if err != nil {
-    return diag.FromErr(err)
+    return apiErrorDiag("Failed to create router", err)
   }
 
   d.SetId(resourceResp.Name)
@@ -97,27 +83,13 @@ func resourceRouterRead(ctx context.Context, d *schema.ResourceData, m interface
   c := m.(*Client)
   var diags diag.Diagnostics
 
-  name := d.Id()
-  project := d.Get("project").(string)
-  path := fmt.Sprintf("/routers/%s?project_name=%s", name, url.QueryEscape(project))
-  req, err := c.newRequest("GET", path)
-  if err != nil {
-    return diag.FromErr(err)
-  }
-
-  resp, err := c.httpClient.Do(req)
-  if err != nil {
-    return diag.FromErr(err)
-  }
-  defer resp.Body.Close()
-
-  if resp.StatusCode == http.StatusNotFound {
+  _, err := c.api.GetRouter(ctx, d.Get("project").(string), d.Id())
+  if faxter.IsNotFound(err) {
     d.SetId("")
     return diags
   }
-
-  if resp.StatusCode != http.StatusOK {
-    return diag.Errorf("Failed to read router: %s", resp.Status)
+  if err != nil {
+    return apiErrorDiag("Failed to read router", err)
   }
 
   // If needed, parse and update fields
@@ -132,38 +104,29 @@ func resourceRouterUpdate(ctx context.Context, d *schema.ResourceData, m interfa
   project := d.Get("project").(string)
   newName := d.Get("name").(string)
   connectExternal := d.Get("connect_external").(bool)
-  subnetsIface := d.Get("subnets").([]interface{})
-  var subnets []string
-  for _, s := range subnetsIface {
-    subnets = append(subnets, s.(string))
+  subnets, err := resolveRouterSubnets(ctx, c, project, d)
+  if err != nil {
+    return diag.FromErr(err)
   }
 
-  updateBody := &RouterCreateRequest{
+  updateBody := &faxter.RouterCreateRequest{
     Project:         project,
     Name:            newName,
     ConnectExternal: connectExternal,
     Subnets:         subnets,
   }
 
-  bodyBytes, _ := json.Marshal(updateBody)
-  path := fmt.Sprintf("/routers/%s?project_name=%s", oldName, url.QueryEscape(project))
-  req, err := c.newRequest("PUT", path)
-  if err != nil {
-    return diag.FromErr(err)
+  err = c.api.UpdateRouter(ctx, project, oldName, updateBody)
+  if faxter.IsNotFound(err) {
+    return resourceGone(d, "router")
   }
-  req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
-
-  resp, err := c.httpClient.Do(req)
   if err != nil {
-    return diag.FromErr(err)
+    return apiErrorDiag("Failed to update router", err)
   }
-  defer resp.Body.Close()
 
-  if resp.StatusCode != 200 {
-    return diag.Errorf("Failed to update router: %s", resp.Status)
-  }
-
-  d.SetId(newName)
+  diags = append(diags, applyRename(ctx, c, d, "router", func(name string) string {
+    return faxter.ObjectPath("routers", project, name)
+  }, oldName, newName)...)
   return diags
 }
 
@@ -171,24 +134,46 @@ func resourceRouterDelete(ctx context.Context, d *schema.ResourceData, m interfa
   c := m.(*Client)
   var diags diag.Diagnostics
 
-  name := d.Id()
-  project := d.Get("project").(string)
-  path := fmt.Sprintf("/routers/%s?project_name=%s", name, url.QueryEscape(project))
-  req, err := c.newRequest("DELETE", path)
+  err := c.api.DeleteRouter(ctx, d.Get("project").(string), d.Id())
+  if faxter.IsNotFound(err) {
+    return resourceGone(d, "router")
+  }
   if err != nil {
-    return diag.FromErr(err)
+    return apiErrorDiag("Failed to delete router", err)
   }
 
-  resp, err := c.httpClient.Do(req)
-  if err != nil {
-    return diag.FromErr(err)
+  d.SetId("")
+  return diags
+}
+
+// resolveRouterSubnets returns the explicitly listed subnets followed by the
+// subnets of every listed network, without duplicates.
+func resolveRouterSubnets(ctx context.Context, c *Client, project string, d *schema.ResourceData) ([]string, error) {
+  var subnets []string
+  seen := map[string]bool{}
+  add := func(subnet string) {
+    if !seen[subnet] {
+      seen[subnet] = true
+      subnets = append(subnets, subnet)
+    }
   }
-  defer resp.Body.Close()
 
-  if resp.StatusCode != http.StatusOK {
-    return diag.Errorf("Failed to delete router: %s", resp.Status)
+  for _, s := range expandStringList(d.Get("subnets").([]interface{})) {
+    add(s)
   }
 
-  d.SetId("")
-  return diags
+  for _, networkName := range expandStringList(d.Get("networks").([]interface{})) {
+    network, err := getNetwork(ctx, c, project, networkName)
+    if err != nil {
+      return nil, fmt.Errorf("failed to resolve subnets of network '%s': %w", networkName, err)
+    }
+    if len(network.Properties.Subnets) == 0 {
+      return nil, fmt.Errorf("network '%s' has no subnets to attach", networkName)
+    }
+    for _, subnet := range network.Properties.Subnets {
+      add(subnet.ID)
+    }
+  }
+
+  return subnets, nil
 }
\ No newline at end of file
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// resourceScript runs a command once on a server through the server agent,
+// so no SSH access to the server is needed. Changing any argument, including
+// triggers, runs it again; destroying it only forgets the run.
+func resourceScript() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceScriptCreate,
+		ReadContext:   resourceScriptRead,
+		DeleteContext: resourceScriptDelete,
+		CustomizeDiff: customizeDiffProject,
+
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"server": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Name of the server to run the command on.",
+			},
+			"command": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				Description:  "Command to run. With script set, the interpreter the script is passed to, e.g. /bin/bash.",
+			},
+			"script": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Script body uploaded to the server and run with command.",
+			},
+			"triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Arbitrary values that run the command again when they change.",
+			},
+			"timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "5m",
+				ValidateFunc: validateDuration,
+				Description:  "How long the command may run before it is treated as failed.",
+			},
+			"exit_code": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"output": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Combined stdout and stderr of the command.",
+			},
+		},
+	}
+}
+
+func resourceScriptCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	project := d.Get("project").(string)
+	server := d.Get("server").(string)
+	timeout, _ := time.ParseDuration(d.Get("timeout").(string))
+
+	exec, err := c.api.StartExec(ctx, project, server, &faxter.ExecRequest{
+		Command:        d.Get("command").(string),
+		Script:         d.Get("script").(string),
+		TimeoutSeconds: int(timeout.Seconds()),
+	})
+	if faxter.IsNotFound(err) {
+		return diag.Errorf("Server '%s' not found in project '%s'", server, project)
+	}
+	if err != nil {
+		return apiErrorDiag(fmt.Sprintf("Failed to run command on server '%s'", server), err)
+	}
+	if exec.ID == "" {
+		return diag.Errorf("No execution ID returned in exec response")
+	}
+	d.SetId(fmt.Sprintf("%s/%s", server, exec.ID))
+
+	// Allow the agent a poll interval past the command's own timeout to
+	// report the result.
+	deadline := time.Now().Add(timeout + c.pollSchedule[0])
+	for attempt := 0; exec.Status == "running"; attempt++ {
+		delay := pollDelay(c.pollSchedule, attempt)
+		if time.Now().Add(delay).After(deadline) {
+			return diag.Errorf("Timed out after %s waiting for the command on server '%s' to finish", d.Get("timeout").(string), server)
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return diag.FromErr(ctx.Err())
+		}
+
+		exec, err = c.api.GetExec(ctx, project, server, exec.ID)
+		if err != nil {
+			return apiErrorDiag("Failed to read command status", err)
+		}
+		tflog.Debug(ctx, "Polled command status", map[string]interface{}{
+			"server": server,
+			"id":     exec.ID,
+			"status": exec.Status,
+		})
+	}
+
+	d.Set("output", exec.Output)
+	if exec.ExitCode != nil {
+		d.Set("exit_code", *exec.ExitCode)
+	}
+
+	// Leaving the ID set taints the resource, so the command runs again on
+	// the next apply.
+	if exec.Status != "completed" || exec.ExitCode == nil || *exec.ExitCode != 0 {
+		exitCode := "unknown"
+		if exec.ExitCode != nil {
+			exitCode = fmt.Sprint(*exec.ExitCode)
+		}
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Command on server '%s' failed with exit code %s", server, exitCode),
+			Detail:   strings.TrimSpace(exec.Output),
+		}}
+	}
+
+	return nil
+}
+
+// resourceScriptRead keeps the recorded run. Its output describes a past
+// execution, so there is nothing to refresh from the API.
+func resourceScriptRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return nil
+}
+
+func resourceScriptDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	d.SetId("")
+	return nil
+}
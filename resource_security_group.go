@@ -2,48 +2,45 @@ package main
 
 import (
   "context"
-  "encoding/json"
   "fmt"
-  "io"
-  "bytes"
-  "net/http"
-  "net/url"
 
+  "github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
   "github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+  "github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
   "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+  "github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
-type SecurityGroupRuleRequest struct {
-  Protocol         string `json:"protocol,omitempty"`
-  PortRangeMin     int    `json:"port_range_min,omitempty"`
-  PortRangeMax     int    `json:"port_range_max,omitempty"`
-  Direction        string `json:"direction,omitempty"`
-  RemoteIpPrefix   string `json:"remote_ip_prefix,omitempty"`
-  RemoteGroupId    string `json:"remote_group_id,omitempty"`
-  EtherType        string `json:"ether_type,omitempty"`
-}
-
-type SecurityGroupCreateRequest struct {
-  Project string                    `json:"project,omitempty"`
-  Name    string                    `json:"name"`
-  Rules   []SecurityGroupRuleRequest `json:"rules"`
-}
-
 func resourceSecurityGroup() *schema.Resource {
   return &schema.Resource{
     CreateContext: resourceSecurityGroupCreate,
     ReadContext:   resourceSecurityGroupRead,
     UpdateContext: resourceSecurityGroupUpdate,
     DeleteContext: resourceSecurityGroupDelete,
+    CustomizeDiff: customdiff.All(
+      customizeDiffProject,
+      customizeDiffSecurityGroup,
+    ),
+    Importer: &schema.ResourceImporter{
+      StateContext: importProjectScoped,
+    },
 
     Schema: map[string]*schema.Schema{
       "project": {
         Type:     schema.TypeString,
-        Required: true,
+        Optional: true,
+        Computed: true,
       },
       "name": {
-        Type:     schema.TypeString,
-        Required: true,
+        Type:         schema.TypeString,
+        Required:     true,
+        ValidateFunc: validateName,
+      },
+      "manage_rules": {
+        Type:        schema.TypeBool,
+        Optional:    true,
+        Default:     true,
+        Description: "Whether the rules blocks define the group's complete rule set. Set to false, with no rules blocks, when the rules are managed by faxter_security_group_rule resources: updates then leave the group's rules in place, so moving inline rules to standalone resources doesn't delete and recreate them.",
       },
       "rules": {
         Type:     schema.TypeList,
@@ -73,6 +70,12 @@ func resourceSecurityGroup() *schema.Resource {
               Optional: true,
               Default:  "0.0.0.0/0",
             },
+            "remote_ip_prefixes": {
+              Type:        schema.TypeList,
+              Optional:    true,
+              Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.IsCIDR},
+              Description: "CIDRs to allow; expanded into one API rule per prefix. Takes precedence over remote_ip_prefix.",
+            },
             "remote_group_id": {
               Type:     schema.TypeString,
               Optional: true,
@@ -96,48 +99,17 @@ func resourceSecurityGroupCreate(ctx context.Context, d *schema.ResourceData, m
   project := d.Get("project").(string)
   name := d.Get("name").(string)
 
-  rules := d.Get("rules").([]interface{})
-  var sgRules []SecurityGroupRuleRequest
-  for _, r := range rules {
-    ruleMap := r.(map[string]interface{})
-    sgRules = append(sgRules, SecurityGroupRuleRequest{
-      Protocol:       ruleMap["protocol"].(string),
-      PortRangeMin:   ruleMap["port_range_min"].(int),
-      PortRangeMax:   ruleMap["port_range_max"].(int),
-      Direction:      ruleMap["direction"].(string),
-      RemoteIpPrefix: ruleMap["remote_ip_prefix"].(string),
-      RemoteGroupId:  ruleMap["remote_group_id"].(string),
-      EtherType:      ruleMap["ether_type"].(string),
-    })
-  }
+  sgRules := expandSecurityGroupRules(d.Get("rules").([]interface{}))
 
-  reqData := &SecurityGroupCreateRequest{
+  reqData := &faxter.SecurityGroupCreateRequest{
     Project: project,
     Name:    name,
     Rules:   sgRules,
   }
 
-  bodyBytes, _ := json.Marshal(reqData)
-  req, err := c.newRequest("POST", "/security_groups/")
-  if err != nil {
-    return diag.FromErr(err)
-  }
-  req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
-
-  resp, err := c.httpClient.Do(req)
-  if err != nil {
-    return diag.FromErr(err)
-  }
-  defer resp.Body.Close()
-
-  if resp.StatusCode != http.StatusOK {
-    return diag.Errorf("Failed to create security group: %s", resp.Status)
-  }
-
-  var resourceResp ResourceResponse
-  err = json.NewDecoder(resp.Body).Decode(&resourceResp)
+  resourceResp, err := c.api.CreateSecurityGroup(ctx, reqData)
   if err != nil {
-    return diag.FromErr(err)
+    return apiErrorDiag("Failed to create security group", err)
   }
 
   d.SetId(resourceResp.Name)
@@ -148,27 +120,13 @@ func resourceSecurityGroupRead(ctx context.Context, d *schema.ResourceData, m in
   c := m.(*Client)
   var diags diag.Diagnostics
 
-  name := d.Id()
-  project := d.Get("project").(string)
-  path := fmt.Sprintf("/security_groups/%s?project_name=%s", name, url.QueryEscape(project))
-  req, err := c.newRequest("GET", path)
-  if err != nil {
-    return diag.FromErr(err)
-  }
-
-  resp, err := c.httpClient.Do(req)
-  if err != nil {
-    return diag.FromErr(err)
-  }
-  defer resp.Body.Close()
-
-  if resp.StatusCode == http.StatusNotFound {
+  _, err := c.api.GetSecurityGroup(ctx, d.Get("project").(string), d.Id())
+  if faxter.IsNotFound(err) {
     d.SetId("")
     return diags
   }
-
-  if resp.StatusCode != http.StatusOK {
-    return diag.Errorf("Failed to read security group: %s", resp.Status)
+  if err != nil {
+    return apiErrorDiag("Failed to read security group", err)
   }
 
   // If needed, parse response to update fields
@@ -183,46 +141,37 @@ func resourceSecurityGroupUpdate(ctx context.Context, d *schema.ResourceData, m
   project := d.Get("project").(string)
   newName := d.Get("name").(string)
 
-  rules := d.Get("rules").([]interface{})
-  var sgRules []SecurityGroupRuleRequest
-  for _, r := range rules {
-    ruleMap := r.(map[string]interface{})
-    sgRules = append(sgRules, SecurityGroupRuleRequest{
-      Protocol:       ruleMap["protocol"].(string),
-      PortRangeMin:   ruleMap["port_range_min"].(int),
-      PortRangeMax:   ruleMap["port_range_max"].(int),
-      Direction:      ruleMap["direction"].(string),
-      RemoteIpPrefix: ruleMap["remote_ip_prefix"].(string),
-      RemoteGroupId:  ruleMap["remote_group_id"].(string),
-      EtherType:      ruleMap["ether_type"].(string),
-    })
-  }
-
-  updateBody := &SecurityGroupCreateRequest{
+  sgRules := expandSecurityGroupRules(d.Get("rules").([]interface{}))
+  if !d.Get("manage_rules").(bool) {
+    // The update replaces the rule set, so send back the rules the group
+    // has now, including those of faxter_security_group_rule resources.
+    current, err := listSecurityGroupRules(ctx, c, project, oldName)
+    if err != nil {
+      return diag.FromErr(err)
+    }
+    sgRules = make([]faxter.SecurityGroupRuleRequest, 0, len(current))
+    for _, rule := range current {
+      sgRules = append(sgRules, rule.SecurityGroupRuleRequest)
+    }
+  }
+
+  updateBody := &faxter.SecurityGroupCreateRequest{
     Project: project,
     Name:    newName,
     Rules:   sgRules,
   }
 
-  bodyBytes, _ := json.Marshal(updateBody)
-  path := fmt.Sprintf("/security_groups/%s?project_name=%s", oldName, url.QueryEscape(project))
-  req, err := c.newRequest("PUT", path)
-  if err != nil {
-    return diag.FromErr(err)
+  err := c.api.UpdateSecurityGroup(ctx, project, oldName, updateBody)
+  if faxter.IsNotFound(err) {
+    return resourceGone(d, "security group")
   }
-  req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
-
-  resp, err := c.httpClient.Do(req)
   if err != nil {
-    return diag.FromErr(err)
+    return apiErrorDiag("Failed to update security group", err)
   }
-  defer resp.Body.Close()
 
-  if resp.StatusCode != 200 {
-    return diag.Errorf("Failed to update security group: %s", resp.Status)
-  }
-
-  d.SetId(newName)
+  diags = append(diags, applyRename(ctx, c, d, "security group", func(name string) string {
+    return faxter.ObjectPath("security_groups", project, name)
+  }, oldName, newName)...)
   return diags
 }
 
@@ -230,24 +179,52 @@ func resourceSecurityGroupDelete(ctx context.Context, d *schema.ResourceData, m
   c := m.(*Client)
   var diags diag.Diagnostics
 
-  name := d.Id()
-  project := d.Get("project").(string)
-  path := fmt.Sprintf("/security_groups/%s?project_name=%s", name, url.QueryEscape(project))
-  req, err := c.newRequest("DELETE", path)
-  if err != nil {
-    return diag.FromErr(err)
+  err := c.api.DeleteSecurityGroup(ctx, d.Get("project").(string), d.Id())
+  if faxter.IsNotFound(err) {
+    return resourceGone(d, "security group")
   }
-
-  resp, err := c.httpClient.Do(req)
   if err != nil {
-    return diag.FromErr(err)
-  }
-  defer resp.Body.Close()
-
-  if resp.StatusCode != http.StatusOK {
-    return diag.Errorf("Failed to delete security group: %s", resp.Status)
+    return apiErrorDiag("Failed to delete security group", err)
   }
 
   d.SetId("")
   return diags
+}
+
+// customizeDiffSecurityGroup rejects rules blocks on a group whose rules are
+// managed elsewhere, since they would never be applied.
+func customizeDiffSecurityGroup(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+  if !d.Get("manage_rules").(bool) && len(d.Get("rules").([]interface{})) > 0 {
+    return fmt.Errorf("rules: must not be set when manage_rules is false; manage the rules with faxter_security_group_rule instead")
+  }
+  return nil
+}
+
+// expandSecurityGroupRules converts the rules blocks into API rules. A rule
+// with remote_ip_prefixes becomes one API rule per prefix.
+func expandSecurityGroupRules(rules []interface{}) []faxter.SecurityGroupRuleRequest {
+  var sgRules []faxter.SecurityGroupRuleRequest
+  for _, r := range rules {
+    ruleMap := r.(map[string]interface{})
+    rule := faxter.SecurityGroupRuleRequest{
+      Protocol:       ruleMap["protocol"].(string),
+      PortRangeMin:   ruleMap["port_range_min"].(int),
+      PortRangeMax:   ruleMap["port_range_max"].(int),
+      Direction:      ruleMap["direction"].(string),
+      RemoteIpPrefix: ruleMap["remote_ip_prefix"].(string),
+      RemoteGroupId:  ruleMap["remote_group_id"].(string),
+      EtherType:      ruleMap["ether_type"].(string),
+    }
+
+    prefixes := expandStringList(ruleMap["remote_ip_prefixes"].([]interface{}))
+    if len(prefixes) == 0 {
+      sgRules = append(sgRules, rule)
+      continue
+    }
+    for _, prefix := range prefixes {
+      rule.RemoteIpPrefix = prefix
+      sgRules = append(sgRules, rule)
+    }
+  }
+  return sgRules
 }
\ No newline at end of file
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceSecurityGroupRule() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceSecurityGroupRuleCreate,
+		ReadContext:   resourceSecurityGroupRuleRead,
+		UpdateContext: resourceSecurityGroupRuleUpdate,
+		DeleteContext: resourceSecurityGroupRuleDelete,
+		CustomizeDiff: customizeDiffProject,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceSecurityGroupRuleImport,
+		},
+
+		// Rules are immutable in the API, so every argument of the rule itself
+		// forces a new rule.
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"security_group": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Name of the security group the rule belongs to.",
+			},
+			"protocol": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  "tcp",
+			},
+			"port_range_min": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				ForceNew: true,
+			},
+			"port_range_max": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				ForceNew: true,
+			},
+			"direction": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  "ingress",
+			},
+			"remote_ip_prefix": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  "0.0.0.0/0",
+			},
+			"remote_group_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"ether_type": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  "IPv4",
+			},
+			"adopt_existing": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Adopt an identical rule the group already has instead of failing. Set it when moving a rule from the inline rules of a group with manage_rules = false; the rule must not be managed by the group or by another faxter_security_group_rule. Only used on create.",
+			},
+		},
+	}
+}
+
+func securityGroupRulePath(project, securityGroup, ruleID string) string {
+	return fmt.Sprintf("/security_groups/%s/rules/%s", url.PathEscape(securityGroup), url.PathEscape(ruleID)) + "?project_name=" + url.QueryEscape(project)
+}
+
+// parseSecurityGroupRuleID splits a rule ID of the form
+// "<security_group>/<rule_id>".
+func parseSecurityGroupRuleID(id string) (string, string, error) {
+	parts := strings.SplitN(id, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("unexpected security group rule ID %q, expected <security_group>/<rule_id>", id)
+	}
+	return parts[0], parts[1], nil
+}
+
+func resourceSecurityGroupRuleCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+
+	project := d.Get("project").(string)
+	securityGroup := d.Get("security_group").(string)
+
+	reqData := &faxter.SecurityGroupRuleRequest{
+		Protocol:       d.Get("protocol").(string),
+		PortRangeMin:   d.Get("port_range_min").(int),
+		PortRangeMax:   d.Get("port_range_max").(int),
+		Direction:      d.Get("direction").(string),
+		RemoteIpPrefix: d.Get("remote_ip_prefix").(string),
+		RemoteGroupId:  d.Get("remote_group_id").(string),
+		EtherType:      d.Get("ether_type").(string),
+	}
+
+	// The API would create an identical rule a second time. An existing one
+	// is only adopted when asked to, e.g. for a rule moved from the inline
+	// rules of a group that no longer manages them; otherwise it belongs to
+	// the group or to another rule resource, and sharing its ID would let
+	// either delete the other's rule.
+	existing, err := listSecurityGroupRules(ctx, c, project, securityGroup)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	for _, rule := range existing {
+		if rule.SecurityGroupRuleRequest != *reqData {
+			continue
+		}
+		if !d.Get("adopt_existing").(bool) {
+			return diag.Errorf("Security group '%s' already has an identical rule (%s). Import it with the ID %s/%s/%s, or set adopt_existing if it was moved from the inline rules of a group with manage_rules = false", securityGroup, rule.ID, project, securityGroup, rule.ID)
+		}
+		tflog.Info(ctx, "Adopting existing security group rule", map[string]interface{}{
+			"security_group": securityGroup,
+			"rule_id":        rule.ID,
+		})
+		d.SetId(securityGroup + "/" + rule.ID)
+		return resourceSecurityGroupRuleRead(ctx, d, m)
+	}
+
+	rule, err := c.api.CreateSecurityGroupRule(ctx, project, securityGroup, reqData)
+	if err != nil {
+		return apiErrorDiag("Failed to create security group rule", err)
+	}
+
+	d.SetId(securityGroup + "/" + rule.ID)
+	return resourceSecurityGroupRuleRead(ctx, d, m)
+}
+
+func resourceSecurityGroupRuleRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+	var diags diag.Diagnostics
+
+	securityGroup, ruleID, err := parseSecurityGroupRuleID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	rule, err := c.api.GetSecurityGroupRule(ctx, d.Get("project").(string), securityGroup, ruleID)
+	if faxter.IsNotFound(err) {
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return apiErrorDiag("Failed to read security group rule", err)
+	}
+
+	if err := setSecurityGroupRule(d, securityGroup, *rule); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
+
+// resourceSecurityGroupRuleUpdate only records a change of adopt_existing,
+// which has no effect after create; everything else forces a new rule.
+func resourceSecurityGroupRuleUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return resourceSecurityGroupRuleRead(ctx, d, m)
+}
+
+func resourceSecurityGroupRuleDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Client)
+	var diags diag.Diagnostics
+
+	securityGroup, ruleID, err := parseSecurityGroupRuleID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = c.api.DeleteSecurityGroupRule(ctx, d.Get("project").(string), securityGroup, ruleID)
+	if err != nil && !faxter.IsNotFound(err) {
+		return apiErrorDiag("Failed to delete security group rule", err)
+	}
+
+	d.SetId("")
+	return diags
+}
+
+// resourceSecurityGroupRuleImport accepts "<project>/<security_group>/<rule_id>".
+func resourceSecurityGroupRuleImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), "/", 3)
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("unexpected import ID %q, expected <project>/<security_group>/<rule_id>", d.Id())
+	}
+
+	d.Set("project", parts[0])
+	d.SetId(parts[1] + "/" + parts[2])
+	return []*schema.ResourceData{d}, nil
+}
+
+func setSecurityGroupRule(d *schema.ResourceData, securityGroup string, rule faxter.SecurityGroupRuleResponse) error {
+	values := map[string]interface{}{
+		"security_group":   securityGroup,
+		"protocol":         rule.Protocol,
+		"port_range_min":   rule.PortRangeMin,
+		"port_range_max":   rule.PortRangeMax,
+		"direction":        rule.Direction,
+		"remote_ip_prefix": rule.RemoteIpPrefix,
+		"remote_group_id":  rule.RemoteGroupId,
+		"ether_type":       rule.EtherType,
+	}
+	for k, v := range values {
+		if err := d.Set(k, v); err != nil {
+			return fmt.Errorf("error setting %s: %s", k, err)
+		}
+	}
+	return nil
+}
+
+// listSecurityGroupRules fetches every rule of a security group.
+func listSecurityGroupRules(ctx context.Context, c *Client, project, securityGroup string) ([]faxter.SecurityGroupRuleResponse, error) {
+	rules, err := c.api.ListSecurityGroupRules(ctx, project, securityGroup)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list security group rules: %w", err)
+	}
+	return rules, nil
+}
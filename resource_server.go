@@ -1,83 +1,78 @@
 package main
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"net/url"
+	"net"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
-type ServerCreateRequest struct {
-	Project           string   `json:"project,omitempty"`
-	Name              string   `json:"name"`
-	Flavor            string   `json:"flavor,omitempty"`
-	Image             string   `json:"image,omitempty"`
-	KeyName           string   `json:"key_name"`
-	SecurityGroups    []string `json:"security_groups,omitempty"`
-	RequestFloatingIP bool     `json:"request_floating_ip"`
-	CloudInit         string   `json:"cloud_init,omitempty"`
-	Networks          []string `json:"networks,omitempty"`
-	SubNetworks       []string `json:"sub_networks,omitempty"`
-	Volumes           []string `json:"volumes,omitempty"`
-}
-
-type ServerUpdateRequest struct {
-	Name              string    `json:"name"` // required by ServerUpdate schema
-	Flavor            *string   `json:"flavor,omitempty"`
-	Image             *string   `json:"image,omitempty"`
-	SecurityGroups    *[]string `json:"security_groups,omitempty"`
-	RequestFloatingIP *bool     `json:"request_floating_ip,omitempty"`
-	Networks          *[]string `json:"networks,omitempty"`
-	SubNetworks       *[]string `json:"subnetworks,omitempty"`
-	Volumes           *[]string `json:"volumes,omitempty"`
-}
-
-type ResourceResponse struct {
-	Name       string `json:"name"`
-	Status     string `json:"status"`
-	Properties struct {
-		IPAddresses     []string `json:"ip_addresses"`
-		RequestFloating bool     `json:"request_floating_ip"`
-		// Add other fields if needed
-	} `json:"properties"`
-	// ... additional fields if needed
-}
-
 func resourceServer() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceServerCreate,
 		ReadContext:   resourceServerRead,
 		UpdateContext: resourceServerUpdate,
 		DeleteContext: resourceServerDelete,
+		CustomizeDiff: customdiff.All(
+			customizeDiffProject,
+			customizeDiffServerImmutable,
+			customizeDiffReplaceOnUserDataChange,
+			customizeDiffServer,
+			customizeDiffUserData,
+		),
+		Importer: &schema.ResourceImporter{
+			StateContext: importProjectScoped,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+		},
 		Schema: map[string]*schema.Schema{
 			"project": {
 				Type:     schema.TypeString,
 				Optional: true,
-				Default:  "default",
+				Computed: true,
 			},
 			"name": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateName,
 			},
 			"key_name": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: suppressEquivalentReference,
+				Description:      "SSH key installed on the server at creation. Changing it replaces the server.",
 			},
 			"flavor": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "copper",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Server flavor. Defaults to the provider's default_flavor. Changing it resizes the server, and the apply waits until the server is back online with the new flavor.",
 			},
 			"image": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "Ubuntu2204",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				DiffSuppressFunc: suppressEquivalentReference,
+				Description:      "Server image. Defaults to the provider's default_image. Changing it replaces the server, unless rebuild_on_image_change is set.",
+			},
+			"rebuild_on_image_change": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If true, changing image rebuilds the server in place, keeping its name, addresses and volumes, and waits for it to come back online, instead of replacing it. The server's local disk is reinstalled.",
 			},
 			"security_groups": {
 				Type:     schema.TypeList,
@@ -93,16 +88,89 @@ func resourceServer() *schema.Resource {
 				//Default:  true,
 			},
 			"cloud_init": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "",
+				ConflictsWith:    []string{"user_data_base64"},
+				StateFunc:        hashCloudInit,
+				DiffSuppressFunc: suppressEquivalentCloudInit,
+				Description:      "User data passed to cloud-init at creation. Above 16 KiB it is sent gzip-compressed, which cloud-init unpacks by itself; it must fit 64 KiB base64-encoded after that. State holds a SHA-256 of the content, and changes to line endings or trailing whitespace are ignored. Changing it doesn't affect an existing server unless replace_on_cloud_init_change is set.",
+			},
+			"user_data_base64": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"cloud_init"},
+				ValidateFunc:  validation.StringIsBase64,
+				Description:   "User data as base64, for binary or pre-rendered payloads such as the output of cloudinit_config with gzip enabled. It is sent as it is, compressed like cloud_init if it is larger than 16 KiB, and is subject to the same limit.",
+			},
+			"replace_on_cloud_init_change": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If true, a change to cloud_init or user_data_base64 replaces the server, so that the new user data runs on a fresh boot.",
+			},
+			"encrypt_cloud_init": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Encrypt the user data, from cloud_init or user_data_base64, with the project's public key before sending it, so that it never leaves Terraform in plaintext. Only affects creation.",
 			},
 			"networks": {
-				Type:     schema.TypeList,
-				Optional: true,
-				Elem:     &schema.Schema{Type: schema.TypeString},
-				DefaultFunc: func() (interface{}, error) {
-					return []interface{}{"public1"}, nil
+				Type:        schema.TypeList,
+				Optional:    true,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Networks to attach. Defaults to the provider's default_networks.",
+			},
+			"network_qos": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Bandwidth caps for individual network attachments.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"network": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Name of an attached network.",
+						},
+						"bandwidth_limit": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(1),
+							Description:  "Maximum bandwidth of the attachment in Mbit/s.",
+						},
+						"qos_policy": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Name of a faxter_qos_policy to apply to the attachment.",
+						},
+					},
+				},
+			},
+			"allowed_address_pairs": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Extra addresses the server may use on an attached network, e.g. a VRRP or keepalived virtual IP, which the platform's anti-spoofing rules would otherwise drop.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"network": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Name of an attached network.",
+						},
+						"ip_address": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.Any(validation.IsIPAddress, validation.IsCIDR),
+							Description:  "IP address or CIDR the server may use.",
+						},
+						"mac_address": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.IsMACAddress,
+							Description:  "MAC address the pair applies to. Defaults to the interface's own.",
+						},
+					},
 				},
 			},
 			"sub_networks": {
@@ -120,6 +188,82 @@ func resourceServer() *schema.Resource {
 				DefaultFunc: func() (interface{}, error) {
 					return []interface{}{}, nil
 				},
+				Description: "Volumes attached to the server. Changes are made by detaching and then attaching volumes one at a time, each waited on until it is available or in use.",
+			},
+			"secret_refs": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Names of secrets-manager secrets to expose to the instance through its metadata and cloud-init, instead of embedding credentials in cloud_init.",
+			},
+			"scheduler_hints": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Placement hints passed to the scheduler, e.g. { aggregate = \"licensed-pool\" } to pin the server to a faxter_host_aggregate. Changing these replaces the server.",
+			},
+			"metadata": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Free-form key/value labels on the server, e.g. { role = \"web\" }, which faxter_inventory groups servers by.",
+			},
+			"placement_policy": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Name of a faxter_placement_policy to schedule the server under, e.g. to spread a tier's servers across hosts. Changing it replaces the server.",
+			},
+			"security": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				MaxItems:    1,
+				Description: "Disk encryption and confidential compute options. Changing these replaces the server.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"encrypted_local_disks": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Computed:    true,
+							ForceNew:    true,
+							Description: "Encrypt the server's local disks at rest.",
+						},
+						"confidential_vm": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Computed:    true,
+							ForceNew:    true,
+							Description: "Run the server as a confidential VM with memory encryption.",
+						},
+					},
+				},
+			},
+			"admin_username": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Administrator account created on Windows images. When set, the generated password is retrieved into admin_password.",
+			},
+			"admin_password": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Administrator password generated on the first boot of a Windows server. Only set when admin_username is.",
+			},
+			"wait_for_winrm": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Wait during creation until WinRM accepts connections on the server, for Windows images.",
+			},
+			"winrm_port": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      5986,
+				ValidateFunc: validation.IsPortNumber,
+				Description:  "Port of the WinRM listener checked by wait_for_winrm. Defaults to 5986 (HTTPS).",
 			},
 			// New computed attribute to capture IP addresses
 			"ip_addresses": {
@@ -127,10 +271,49 @@ func resourceServer() *schema.Resource {
 				Computed: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
+			"public_ip": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Floating IP address of the server, empty if it has none.",
+			},
+			"private_ips": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Fixed IP addresses of the server on its networks, ordered by network name.",
+			},
+			"network_addresses": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Fixed IP address of the server on each network, by network name. Empty with APIs that don't report addresses by network.",
+			},
 			"status": {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"uuid": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Immutable API identifier of the server, used to find it again if it is renamed outside Terraform.",
+			},
+			"power_state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Power state reported by the hypervisor (e.g. running, shutdown).",
+			},
+			"task_state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Task currently in progress on the server (e.g. resizing, migrating); empty when idle.",
+			},
+			"provisioning_seconds": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Seconds from the create request until the server was first reported online, for tracking provisioning times. Recorded at creation only.",
+			},
+			"lock":            lockSchema(),
+			"properties_json": propertiesJSONSchema(),
 		},
 	}
 }
@@ -142,161 +325,420 @@ func resourceServerCreate(ctx context.Context, d *schema.ResourceData, m interfa
 	project := d.Get("project").(string)
 	name := d.Get("name").(string)
 	flavor := d.Get("flavor").(string)
-	image := d.Get("image").(string)
-	keyName := d.Get("key_name").(string)
+	image := strings.TrimSpace(d.Get("image").(string))
+	keyName := strings.TrimSpace(d.Get("key_name").(string))
 	requestFloatingIP := d.Get("request_floating_ip").(bool)
-	cloudInit := d.Get("cloud_init").(string)
 
 	networks := expandStringList(d.Get("networks").([]interface{}))
-	sub_networks := expandStringList(d.Get("sub_networks").([]interface{}))
-	volumes := expandStringList(d.Get("volumes").([]interface{}))
-	securityGroups := expandStringList(d.Get("security_groups").([]interface{}))
 
-	reqData := &ServerCreateRequest{
-		Project:           project,
-		Name:              name,
-		Flavor:            flavor,
-		Image:             image,
-		KeyName:           keyName,
-		SecurityGroups:    securityGroups,
-		RequestFloatingIP: requestFloatingIP,
-		CloudInit:         cloudInit,
-		Networks:          networks,
-		SubNetworks:       sub_networks,
-		Volumes:           volumes,
+	// Fall back to the provider-level defaults for anything left unset
+	if flavor == "" {
+		flavor = c.defaultFlavor
 	}
-
-	fmt.Printf("%#v\n", reqData)
-
-	bodyBytes, _ := json.Marshal(reqData)
-	req, err := c.newRequest("POST", "/servers/")
-	if err != nil {
-		return diag.FromErr(err)
+	if image == "" {
+		image = c.defaultImage
 	}
-	req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
-
-	fmt.Printf("%#v\n", req)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return diag.FromErr(err)
+	if len(networks) == 0 {
+		networks = c.defaultNetworks
 	}
-	defer resp.Body.Close()
+	d.Set("flavor", flavor)
+	d.Set("image", image)
+	d.Set("networks", networks)
 
-	if resp.StatusCode != 200 {
-		// Attempt to parse the "detail" field from response body
-		body, _ := io.ReadAll(resp.Body)
-		var errorMap map[string]interface{}
-		if err := json.Unmarshal(body, &errorMap); err == nil {
-			if detail, ok := errorMap["detail"].(string); ok {
-				return diag.Errorf("Failed to create server: %s - %s", resp.Status, detail)
-			}
-		}
+	sub_networks := expandStringList(d.Get("sub_networks").([]interface{}))
+	volumes := expandStringList(d.Get("volumes").([]interface{}))
+	securityGroups := expandStringList(d.Get("security_groups").([]interface{}))
 
-		// If no "detail" or JSON parse fails, return a generic error
-		return diag.Errorf("Failed to create server: %s", resp.Status)
+	reqData := &faxter.ServerCreateRequest{
+		Project:             project,
+		Name:                name,
+		Flavor:              flavor,
+		Image:               image,
+		KeyName:             keyName,
+		SecurityGroups:      securityGroups,
+		RequestFloatingIP:   requestFloatingIP,
+		Networks:            networks,
+		SubNetworks:         sub_networks,
+		Volumes:             volumes,
+		Security:            expandServerSecurity(d.Get("security").([]interface{})),
+		SecretRefs:          expandStringList(d.Get("secret_refs").([]interface{})),
+		SchedulerHints:      expandStringMap(d.Get("scheduler_hints").(map[string]interface{})),
+		NetworkQoS:          expandNetworkQoS(d.Get("network_qos").([]interface{})),
+		AllowedAddressPairs: expandAllowedAddressPairs(d.Get("allowed_address_pairs").([]interface{})),
+		AdminUsername:       d.Get("admin_username").(string),
+		PlacementPolicy:     d.Get("placement_policy").(string),
+		Metadata:            expandStringMap(d.Get("metadata").(map[string]interface{})),
 	}
 
-	var resourceResps []ResourceResponse
-	err = json.NewDecoder(resp.Body).Decode(&resourceResps)
-	if err != nil {
+	if err := setServerUserData(ctx, c, d, reqData); err != nil {
 		return diag.FromErr(err)
 	}
 
+	// Provisioning time is measured from the create request.
+	requested := time.Now()
+	var resourceResps []faxter.ResourceResponse
+	if reqDiags := doFloatingIPRequest(ctx, c, project, "create server", func() (err error) {
+		resourceResps, err = c.api.CreateServer(ctx, reqData)
+		return err
+	}); reqDiags.HasError() {
+		return reqDiags
+	}
+
 	// Assume count=1 for simplicity. If multiple, handle accordingly.
 	if len(resourceResps) == 0 {
 		return diag.Errorf("No server returned in create response")
 	}
 
 	d.SetId(resourceResps[0].Name)
+	d.Set("uuid", resourceResps[0].ID)
+
+	// Wait for the server to come online, recording its status as it goes.
+	// The API can report "error" briefly while it retries host selection,
+	// so only fail once the state persists.
+	timeout := d.Timeout(schema.TimeoutCreate)
+	start := time.Now()
+	warned := false
+	errorPolls := 0
+	wait := &retry.StateChangeConf{
+		Pending: []string{"building", "error"},
+		Target:  []string{"online"},
+		Refresh: serverStatusRefresh(ctx, c, project, d.Id(), func(server *faxter.ResourceResponse) (string, error) {
+			tflog.Debug(ctx, "Polled server status", map[string]interface{}{
+				"server":     name,
+				"status":     server.Status,
+				"task_state": server.Properties.TaskState,
+				"elapsed":    time.Since(start).Round(time.Second).String(),
+			})
 
-	// Implement polling to wait until the server status is "online"
-	pollTimeout := 5 * time.Minute
-	pollInterval := 10 * time.Second
-	deadline := time.Now().Add(pollTimeout)
+			// Update the status, states and ip_addresses in the Terraform state
+			if setDiags := setServerStatus(d, server); setDiags.HasError() {
+				return "", fmt.Errorf("%s", setDiags[0].Summary)
+			}
 
-	for {
-		// Wait for the next poll interval
-		time.Sleep(pollInterval)
+			switch server.Status {
+			case "online":
+				return "online", nil
+			case "error":
+				errorPolls++
+				if errorPolls >= c.errorRetries {
+					return "", fmt.Errorf("server '%s' is in an error state%s", name, serverErrorReason(ctx, c, project, name))
+				}
+				tflog.Warn(ctx, "Server reported an error state, waiting to see if it recovers", map[string]interface{}{
+					"server":      name,
+					"error_polls": errorPolls,
+				})
+				return "error", nil
+			}
+			errorPolls = 0
 
-		// Check if context is done
+			// Once past half the timeout, warn once rather than waiting silently
+			if !warned && time.Since(start) > timeout/2 {
+				warned = true
+				diags = append(diags, slowWaitWarning(ctx, "server", name, server, time.Since(start), timeout-time.Since(start)))
+			}
+			// Any other status is a step on the way to online.
+			return "building", nil
+		}),
+		Timeout: timeout,
+		// The shared poller paces the polls along the provider's
+		// poll_schedule, so don't wait on top of it.
+		PollInterval: time.Millisecond,
+		// A new server can take a moment to show up in listings.
+		NotFoundChecks: 3,
+	}
+	if _, err := wait.WaitForStateContext(ctx); err != nil {
 		if ctx.Err() != nil {
-			return diag.FromErr(ctx.Err())
+			return append(diags, diag.FromErr(ctx.Err())...)
 		}
+		var timeoutErr *retry.TimeoutError
+		if errors.As(err, &timeoutErr) {
+			return append(diags, diag.Errorf("Timed out waiting for server '%s' to become online", name)...)
+		}
+		return append(diags, diag.Errorf("Error waiting for server '%s': %s", name, err)...)
+	}
 
-		// Read the current server status
-		currentStatus, ipAddresses, _, err := getServerStatus(ctx, c, project, d.Id())
+	provisioned := time.Since(requested).Round(time.Second)
+	tflog.Info(ctx, "Server provisioned", map[string]interface{}{
+		"server":               name,
+		"flavor":               flavor,
+		"image":                image,
+		"provisioning_seconds": int(provisioned.Seconds()),
+	})
+	if err := d.Set("provisioning_seconds", int(provisioned.Seconds())); err != nil {
+		return append(diags, diag.Errorf("Error setting provisioning_seconds: %s", err)...)
+	}
+
+	// Windows images finish their first boot after the server is online.
+	if reqData.AdminUsername != "" {
+		password, err := waitForAdminPassword(ctx, c, project, name)
 		if err != nil {
-			return diag.Errorf("Error fetching server status: %s", err)
+			return append(diags, diag.FromErr(err)...)
+		}
+		d.Set("admin_password", password)
+	}
+	if d.Get("wait_for_winrm").(bool) {
+		addresses := expandStringList(d.Get("ip_addresses").([]interface{}))
+		if err := waitForWinRM(ctx, c, name, addresses, d.Get("winrm_port").(int)); err != nil {
+			return append(diags, diag.FromErr(err)...)
 		}
+	}
 
-		// Update the status in the Terraform state
-		if err := d.Set("status", currentStatus); err != nil {
-			return diag.Errorf("Error setting status: %s", err)
+	if d.Get("lock").(bool) {
+		if err := setLock(ctx, c, "servers", project, name, true); err != nil {
+			return append(diags, diag.FromErr(err)...)
 		}
+	}
 
-		// If status is "online", proceed to set ip_addresses and exit the loop
-		if currentStatus == "online" {
-			if err := d.Set("ip_addresses", ipAddresses); err != nil {
-				return diag.Errorf("Error setting ip_addresses: %s", err)
-			}
-			break
+	return diags
+}
+
+// serverErrorReason returns the message of the server's latest error event,
+// formatted to follow an error summary, or "" when none can be found.
+func serverErrorReason(ctx context.Context, c *Client, project, name string) string {
+	events, err := c.api.ListServerEvents(ctx, project, name)
+	if err != nil {
+		tflog.Debug(ctx, "Failed to fetch server events", map[string]interface{}{
+			"server": name,
+			"error":  err.Error(),
+		})
+		return ""
+	}
+	for i := len(events) - 1; i >= 0; i-- {
+		if events[i].Type == "error" && events[i].Message != "" {
+			return ": " + events[i].Message
 		}
+	}
+	return ""
+}
 
-		// if status is "error", return an error
-		if currentStatus == "error" {
-			return diag.Errorf("Server '%s' is in an error state", name)
+// serverStatusRefresh returns a retry.StateRefreshFunc reading the server's
+// status through the shared poller, which batches the lookups of every
+// server being waited on and backs off along the provider's poll_schedule.
+// observe sees each server read and returns the state to report, so that
+// waits can group the API's statuses into their own Pending and Target
+// states. A server missing from the listing is reported as not found.
+func serverStatusRefresh(ctx context.Context, c *Client, project, name string, observe func(*faxter.ResourceResponse) (string, error)) retry.StateRefreshFunc {
+	attempt := 0
+	return func() (interface{}, string, error) {
+		server, err := c.serverStatus.status(ctx, c, project, name, pollDelay(c.pollSchedule, attempt))
+		attempt++
+		if errors.Is(err, errNotFound) {
+			return nil, "", nil
 		}
+		if err != nil {
+			return nil, "", fmt.Errorf("fetching server status: %w", err)
+		}
+		state, err := observe(server)
+		if err != nil {
+			return nil, "", err
+		}
+		return server, state, nil
+	}
+}
+
+// waitForServerChange waits after an update the API carries out in the
+// background, such as a resize or a rebuild, until the server is back
+// online, idle, and applied reports the change on it, since the API answers
+// the request at once. change describes the wait in messages, e.g. "be
+// resized to flavor 'large'". An error state that persists for error_retries
+// polls fails the wait with the API's reason.
+func waitForServerChange(ctx context.Context, c *Client, d *schema.ResourceData, project, name, change string, applied func(*faxter.ResourceResponse) bool) diag.Diagnostics {
+	start := time.Now()
+	errorPolls := 0
+	wait := &retry.StateChangeConf{
+		Pending: []string{"changing", "error"},
+		Target:  []string{"online"},
+		Refresh: serverStatusRefresh(ctx, c, project, name, func(server *faxter.ResourceResponse) (string, error) {
+			tflog.Debug(ctx, "Polled server status", map[string]interface{}{
+				"server":     name,
+				"status":     server.Status,
+				"task_state": server.Properties.TaskState,
+				"waiting_to": change,
+				"elapsed":    time.Since(start).Round(time.Second).String(),
+			})
 
-		// Check if the deadline has been reached
-		if time.Now().After(deadline) {
-			return diag.Errorf("Timed out waiting for server '%s' to become online", name)
+			if setDiags := setServerStatus(d, server); setDiags.HasError() {
+				return "", fmt.Errorf("%s", setDiags[0].Summary)
+			}
+
+			if server.Status == "error" {
+				errorPolls++
+				if errorPolls >= c.errorRetries {
+					return "", fmt.Errorf("server '%s' is in an error state%s", name, serverErrorReason(ctx, c, project, name))
+				}
+				return "error", nil
+			}
+			errorPolls = 0
+
+			if server.Status == "online" && server.Properties.TaskState == "" && applied(server) {
+				return "online", nil
+			}
+			return "changing", nil
+		}),
+		Timeout: d.Timeout(schema.TimeoutUpdate),
+		// The shared poller paces the polls along the provider's
+		// poll_schedule, so don't wait on top of it.
+		PollInterval: time.Millisecond,
+	}
+	if _, err := wait.WaitForStateContext(ctx); err != nil {
+		if ctx.Err() != nil {
+			return diag.FromErr(ctx.Err())
 		}
+		var timeoutErr *retry.TimeoutError
+		if errors.As(err, &timeoutErr) {
+			return diag.Errorf("Timed out waiting for server '%s' to %s", name, change)
+		}
+		return diag.Errorf("Error waiting for server '%s' to %s: %s", name, change, err)
 	}
 
-	return diags
+	tflog.Info(ctx, "Server change applied", map[string]interface{}{
+		"server":  name,
+		"change":  change,
+		"seconds": int(time.Since(start).Seconds()),
+	})
+	return nil
 }
 
-// getServerStatus fetches the current status and IP addresses of the server.
-func getServerStatus(ctx context.Context, c *Client, project, name string) (string, []string, bool, error) {
-	// Construct the API path with query parameters
-	path := fmt.Sprintf("/servers/%s?project_name=%s", url.PathEscape(name), url.QueryEscape(project))
-	req, err := c.newRequest("GET", path)
+// getServerStatus fetches the current state of the server from the API.
+// A missing server is reported as an error wrapping errNotFound.
+func getServerStatus(ctx context.Context, c *Client, project, name string) (*faxter.ResourceResponse, error) {
+	server, err := c.api.GetServer(ctx, project, name)
+	if faxter.IsNotFound(err) {
+		return nil, fmt.Errorf("server '%s' %w", name, errNotFound)
+	}
 	if err != nil {
-		return "", nil, true, err
+		return nil, fmt.Errorf("failed to get server status: %w", err)
 	}
+	return server, nil
+}
+
+// slowWaitWarning logs and builds a warning for a resource whose wait has run
+// past half of its timeout.
+func slowWaitWarning(ctx context.Context, kind, name string, server *faxter.ResourceResponse, elapsed, remaining time.Duration) diag.Diagnostic {
+	elapsed = elapsed.Round(time.Second)
+	remaining = remaining.Round(time.Second)
 
-	// Send the HTTP request
-	resp, err := c.httpClient.Do(req.WithContext(ctx))
+	tflog.Warn(ctx, "Still waiting for "+kind+" to become online", map[string]interface{}{
+		"name":       name,
+		"status":     server.Status,
+		"task_state": server.Properties.TaskState,
+		"elapsed":    elapsed.String(),
+		"remaining":  remaining.String(),
+	})
+
+	detail := fmt.Sprintf("The %s '%s' has been waiting %s and is currently '%s'", kind, name, elapsed, server.Status)
+	if server.Properties.TaskState != "" {
+		detail += fmt.Sprintf(" (task: %s)", server.Properties.TaskState)
+	}
+	detail += fmt.Sprintf(". The provider will keep waiting up to %s more before failing.", remaining)
+
+	return diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("Still waiting for %s '%s' to become online", kind, name),
+		Detail:   detail,
+	}
+}
+
+// listServers fetches every server in a project in one request.
+func listServers(ctx context.Context, c *Client, project string) ([]faxter.ResourceResponse, error) {
+	servers, err := c.api.ListServers(ctx, project)
 	if err != nil {
-		return "", nil, true, err
+		return nil, fmt.Errorf("failed to list servers: %w", err)
 	}
-	defer resp.Body.Close()
+	return servers, nil
+}
+
+// splitServerAddresses sorts the addresses of a server into its floating
+// address, its fixed addresses and its fixed address on each network. APIs
+// that only report ip_addresses don't say which address is which, so there
+// private addresses are taken to be fixed and the first public one floating.
+func splitServerAddresses(props *faxter.ServerProperties) (string, []string, map[string]string) {
+	publicIP := ""
+	privateIPs := []string{}
+	networkAddresses := map[string]string{}
 
-	// Handle 404 Not Found
-	if resp.StatusCode == 404 {
-		return "", nil, true, fmt.Errorf("server '%s' not found", name)
+	if props.Addresses == nil {
+		for _, address := range props.IPAddresses {
+			ip := net.ParseIP(address)
+			switch {
+			case ip != nil && ip.IsPrivate():
+				privateIPs = append(privateIPs, address)
+			case publicIP == "":
+				publicIP = address
+			}
+		}
+		return publicIP, privateIPs, networkAddresses
 	}
 
-	// Check for successful response
-	if resp.StatusCode != 200 {
-		// Read response body for error details
-		body, _ := io.ReadAll(resp.Body)
-		return "", nil, true, fmt.Errorf("failed to get server status: %s - %s", resp.Status, string(body))
+	networks := make([]string, 0, len(props.Addresses))
+	for network := range props.Addresses {
+		networks = append(networks, network)
 	}
+	sort.Strings(networks)
+	for _, network := range networks {
+		for _, address := range props.Addresses[network] {
+			switch address.Type {
+			case "floating":
+				if publicIP == "" {
+					publicIP = address.Address
+				}
+			case "fixed":
+				privateIPs = append(privateIPs, address.Address)
+				if _, ok := networkAddresses[network]; !ok {
+					networkAddresses[network] = address.Address
+				}
+			}
+		}
+	}
+	return publicIP, privateIPs, networkAddresses
+}
 
-	// Decode the response
-	var resourceResps ResourceResponse
-	err = json.NewDecoder(resp.Body).Decode(&resourceResps)
-	if err != nil {
-		return "", nil, true, fmt.Errorf("error decoding read response: %s", err)
+// setServerStatus copies the computed attributes reported by the API into state.
+func setServerStatus(d *schema.ResourceData, server *faxter.ResourceResponse) diag.Diagnostics {
+	if err := d.Set("status", server.Status); err != nil {
+		return diag.Errorf("Error setting status: %s", err)
+	}
+
+	if err := d.Set("ip_addresses", server.Properties.IPAddresses); err != nil {
+		return diag.Errorf("Error setting ip_addresses: %s", err)
+	}
+
+	publicIP, privateIPs, networkAddresses := splitServerAddresses(&server.Properties)
+	if err := d.Set("public_ip", publicIP); err != nil {
+		return diag.Errorf("Error setting public_ip: %s", err)
+	}
+	if err := d.Set("private_ips", privateIPs); err != nil {
+		return diag.Errorf("Error setting private_ips: %s", err)
+	}
+	if err := d.Set("network_addresses", networkAddresses); err != nil {
+		return diag.Errorf("Error setting network_addresses: %s", err)
+	}
+
+	if err := d.Set("power_state", server.Properties.PowerState); err != nil {
+		return diag.Errorf("Error setting power_state: %s", err)
+	}
+
+	if err := d.Set("task_state", server.Properties.TaskState); err != nil {
+		return diag.Errorf("Error setting task_state: %s", err)
+	}
+
+	if server.Properties.Security != nil {
+		if err := d.Set("security", flattenServerSecurity(server.Properties.Security)); err != nil {
+			return diag.Errorf("Error setting security: %s", err)
+		}
 	}
 
-	currentStatus := resourceResps.Status
-	ipAddresses := resourceResps.Properties.IPAddresses
-	request_floating := resourceResps.Properties.RequestFloating
+	if server.ID != "" {
+		if err := d.Set("uuid", server.ID); err != nil {
+			return diag.Errorf("Error setting uuid: %s", err)
+		}
+	}
 
-	return currentStatus, ipAddresses, request_floating, nil
+	if err := setPropertiesJSON(d, server.RawProperties); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
 }
 
 // resourceServerRead handles reading the server resource from the API.
@@ -308,26 +750,87 @@ func resourceServerRead(ctx context.Context, d *schema.ResourceData, m interface
 	project := d.Get("project").(string)
 
 	// Read the current server status and IP addresses
-	currentStatus, ipAddresses, request_floating, err := getServerStatus(ctx, c, project, name)
-	if err != nil {
-		if err.Error() == fmt.Sprintf("server '%s' not found", name) {
+	server, err := getServerStatus(ctx, c, project, name)
+	if errors.Is(err, errNotFound) {
+		// The ID is the server name, so a rename outside Terraform looks like a
+		// deletion. Retry with the stored UUID, which the API also accepts in
+		// place of the name, before dropping the server from state.
+		if uuid := d.Get("uuid").(string); uuid != "" {
+			server, err = getServerStatus(ctx, c, project, uuid)
+		}
+		if errors.Is(err, errNotFound) {
 			d.SetId("")
 			return diags
 		}
+		if err == nil && server.Name != name {
+			tflog.Info(ctx, "Server was renamed outside Terraform, reconciling", map[string]interface{}{
+				"old_name": name,
+				"new_name": server.Name,
+				"uuid":     server.ID,
+			})
+			d.SetId(server.Name)
+			if err := d.Set("name", server.Name); err != nil {
+				return diag.Errorf("Error setting name: %s", err)
+			}
+		}
+	}
+	if err != nil {
 		return diag.Errorf("Error reading server: %s", err)
 	}
 
-	// Update the state with status and ip_addresses
-	if err := d.Set("status", currentStatus); err != nil {
-		return diag.Errorf("Error setting status: %s", err)
+	// Update the state with status, states and ip_addresses
+	if diags := setServerStatus(d, server); diags.HasError() {
+		return diags
 	}
 
-	if err := d.Set("ip_addresses", ipAddresses); err != nil {
-		return diag.Errorf("Error setting ip_addresses: %s", err)
+	if err := d.Set("request_floating_ip", server.Properties.RequestFloating); err != nil {
+		return diag.Errorf("Error setting request_floating_ip: %s", err)
 	}
 
-	if err := d.Set("request_floating_ip", request_floating); err != nil {
-		return diag.Errorf("Error setting request_floating_ip: %s", err)
+	// Pairs added or removed through the API show up as drift; older API
+	// deployments don't report them at all.
+	if server.Properties.AllowedAddressPairs != nil {
+		if err := d.Set("allowed_address_pairs", flattenAllowedAddressPairs(server.Properties.AllowedAddressPairs)); err != nil {
+			return diag.Errorf("Error setting allowed_address_pairs: %s", err)
+		}
+	}
+
+	// Labels set through the API show up as drift; older API deployments
+	// don't report them at all.
+	if server.Properties.Metadata != nil {
+		if err := d.Set("metadata", server.Properties.Metadata); err != nil {
+			return diag.Errorf("Error setting metadata: %s", err)
+		}
+	}
+
+	// Older API deployments don't report the flavor; keep the configured
+	// one rather than clearing it.
+	if server.Properties.Flavor != "" {
+		if err := d.Set("flavor", server.Properties.Flavor); err != nil {
+			return diag.Errorf("Error setting flavor: %s", err)
+		}
+	}
+
+	// Record the API's canonical spelling of the references; configuration
+	// that differs only in case or surrounding whitespace is suppressed.
+	if server.Properties.Image != "" {
+		if err := d.Set("image", server.Properties.Image); err != nil {
+			return diag.Errorf("Error setting image: %s", err)
+		}
+	}
+
+	if server.Properties.KeyName != "" {
+		if err := d.Set("key_name", server.Properties.KeyName); err != nil {
+			return diag.Errorf("Error setting key_name: %s", err)
+		}
+	}
+
+	if err := readLock(ctx, c, d, "servers"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if c.deepRefresh {
+		diags = append(diags, checkServerReferences(ctx, c, d)...)
 	}
 
 	return diags
@@ -341,7 +844,7 @@ func resourceServerUpdate(ctx context.Context, d *schema.ResourceData, m interfa
 	project := d.Get("project").(string)
 
 	// According to ServerUpdate schema, name is required
-	updateReq := &ServerUpdateRequest{
+	updateReq := &faxter.ServerUpdateRequest{
 		Name: d.Get("name").(string),
 	}
 
@@ -349,10 +852,6 @@ func resourceServerUpdate(ctx context.Context, d *schema.ResourceData, m interfa
 		flavor := d.Get("flavor").(string)
 		updateReq.Flavor = &flavor
 	}
-	if d.HasChange("image") {
-		image := d.Get("image").(string)
-		updateReq.Image = &image
-	}
 	if d.HasChange("request_floating_ip") {
 		rf := d.Get("request_floating_ip").(bool)
 		updateReq.RequestFloatingIP = &rf
@@ -365,31 +864,91 @@ func resourceServerUpdate(ctx context.Context, d *schema.ResourceData, m interfa
 		subNetworks := expandStringList(d.Get("sub_networks").([]interface{}))
 		updateReq.SubNetworks = &subNetworks
 	}
-	if d.HasChange("volumes") {
-		volumes := expandStringList(d.Get("volumes").([]interface{}))
-		updateReq.Volumes = &volumes
-	}
 	if d.HasChange("security_groups") {
 		securityGroups := expandStringList(d.Get("security_groups").([]interface{}))
 		updateReq.SecurityGroups = &securityGroups
 	}
+	if d.HasChange("network_qos") {
+		networkQoS := expandNetworkQoS(d.Get("network_qos").([]interface{}))
+		if networkQoS == nil {
+			networkQoS = []faxter.NetworkQoS{}
+		}
+		updateReq.NetworkQoS = &networkQoS
+	}
+	if d.HasChange("allowed_address_pairs") {
+		pairs := expandAllowedAddressPairs(d.Get("allowed_address_pairs").([]interface{}))
+		if pairs == nil {
+			pairs = []faxter.AllowedAddressPair{}
+		}
+		updateReq.AllowedAddressPairs = &pairs
+	}
+	if d.HasChange("secret_refs") {
+		secretRefs := expandStringList(d.Get("secret_refs").([]interface{}))
+		if secretRefs == nil {
+			secretRefs = []string{}
+		}
+		updateReq.SecretRefs = &secretRefs
+	}
+	if d.HasChange("metadata") {
+		metadata := expandStringMap(d.Get("metadata").(map[string]interface{}))
+		if metadata == nil {
+			metadata = map[string]string{}
+		}
+		updateReq.Metadata = &metadata
+	}
 
-	bodyBytes, _ := json.Marshal(updateReq)
-	path := fmt.Sprintf("/servers/%s?project_name=%s", url.PathEscape(name), url.QueryEscape(project))
-	req, err := c.newRequest("PUT", path)
+	err := c.api.UpdateServer(ctx, project, name, updateReq)
+	if faxter.IsNotFound(err) {
+		return resourceGone(d, "server")
+	}
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiag("Failed to update server", err)
 	}
 
-	req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return diag.FromErr(err)
+	newName := d.Get("name").(string)
+	if d.HasChange("flavor") {
+		flavor := d.Get("flavor").(string)
+		resized := func(server *faxter.ResourceResponse) bool {
+			// Older API deployments don't report the flavor; being online
+			// and idle again is all they show of a finished resize.
+			return server.Properties.Flavor == "" || suppressEquivalentReference("flavor", server.Properties.Flavor, flavor, nil)
+		}
+		if resizeDiags := waitForServerChange(ctx, c, d, project, newName, fmt.Sprintf("be resized to flavor '%s'", flavor), resized); resizeDiags.HasError() {
+			return append(diags, resizeDiags...)
+		}
+	}
+
+	// An image change only reaches the update when rebuild_on_image_change
+	// is set; otherwise it replaces the server.
+	if d.HasChange("image") {
+		image := d.Get("image").(string)
+		err := c.api.RebuildServer(ctx, project, newName, &faxter.ServerRebuildRequest{Image: image})
+		if faxter.IsNotFound(err) {
+			return resourceGone(d, "server")
+		}
+		if err != nil {
+			return apiErrorDiag("Failed to rebuild server", err)
+		}
+		rebuilt := func(server *faxter.ResourceResponse) bool {
+			return server.Properties.Image == "" || suppressEquivalentReference("image", server.Properties.Image, image, nil)
+		}
+		if rebuildDiags := waitForServerChange(ctx, c, d, project, newName, fmt.Sprintf("be rebuilt from image '%s'", image), rebuilt); rebuildDiags.HasError() {
+			return append(diags, rebuildDiags...)
+		}
+	}
+
+	// Volumes are attached and detached one by one rather than by replacing
+	// the list, so that the steps can be ordered and waited on.
+	if d.HasChange("volumes") {
+		if volumeDiags := updateServerVolumes(ctx, c, d, project, newName); volumeDiags.HasError() {
+			return append(diags, volumeDiags...)
+		}
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		return diag.Errorf("Failed to update server: %s", resp.Status)
+	if d.HasChange("lock") {
+		if err := setLock(ctx, c, "servers", project, d.Get("name").(string), d.Get("lock").(bool)); err != nil {
+			return diag.FromErr(err)
+		}
 	}
 
 	return diags
@@ -401,26 +960,159 @@ func resourceServerDelete(ctx context.Context, d *schema.ResourceData, m interfa
 
 	name := d.Id()
 	project := d.Get("project").(string)
-	path := fmt.Sprintf("/servers/%s?project_name=%s", url.PathEscape(name), url.QueryEscape(project))
-	req, err := c.newRequest("DELETE", path)
-	if err != nil {
+	if err := releaseLock(ctx, c, "servers", project, name); err != nil {
 		return diag.FromErr(err)
 	}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return diag.FromErr(err)
+	err := c.api.DeleteServer(ctx, project, name)
+	if faxter.IsNotFound(err) {
+		return resourceGone(d, "server")
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return diag.Errorf("Failed to delete server: %s", resp.Status)
+	if err != nil {
+		return apiErrorDiag("Failed to delete server", err)
 	}
 
 	d.SetId("")
 	return diags
 }
 
+func expandNetworkQoS(list []interface{}) []faxter.NetworkQoS {
+	var result []faxter.NetworkQoS
+	for _, v := range list {
+		m := v.(map[string]interface{})
+		result = append(result, faxter.NetworkQoS{
+			Network:            m["network"].(string),
+			BandwidthLimitMbps: m["bandwidth_limit"].(int),
+			QoSPolicy:          m["qos_policy"].(string),
+		})
+	}
+	return result
+}
+
+func expandAllowedAddressPairs(list []interface{}) []faxter.AllowedAddressPair {
+	var result []faxter.AllowedAddressPair
+	for _, v := range list {
+		m := v.(map[string]interface{})
+		result = append(result, faxter.AllowedAddressPair{
+			Network:    m["network"].(string),
+			IPAddress:  m["ip_address"].(string),
+			MACAddress: m["mac_address"].(string),
+		})
+	}
+	return result
+}
+
+func flattenAllowedAddressPairs(pairs []faxter.AllowedAddressPair) []interface{} {
+	result := make([]interface{}, 0, len(pairs))
+	for _, p := range pairs {
+		result = append(result, map[string]interface{}{
+			"network":     p.Network,
+			"ip_address":  p.IPAddress,
+			"mac_address": p.MACAddress,
+		})
+	}
+	return result
+}
+
+// immutableServerAttributes are the attributes the API can't change on an
+// existing server. It accepts them in an update but ignores them, or applies
+// them only in part, e.g. recording a new image the disk wasn't built from.
+var immutableServerAttributes = []string{"image", "key_name"}
+
+// customizeDiffServerImmutable plans a replacement when an immutable
+// attribute changes, rather than an update that wouldn't take effect. With
+// rebuild_on_image_change an image change is left to the update, which
+// rebuilds the server in place.
+func customizeDiffServerImmutable(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	for _, attr := range immutableServerAttributes {
+		if attr == "image" && d.Get("rebuild_on_image_change").(bool) {
+			continue
+		}
+		// HasChange doesn't apply the DiffSuppressFunc, so a reference that
+		// only differs in spelling would still count as changed.
+		old, new := d.GetChange(attr)
+		if !d.HasChange(attr) || suppressEquivalentReference(attr, old.(string), new.(string), nil) {
+			continue
+		}
+		if err := d.ForceNew(attr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// customizeDiffServer checks at plan time that allowed address pairs refer
+// to networks the server is attached to, and that the API supports them and
+// placement policies.
+func customizeDiffServer(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Get("placement_policy").(string) != "" {
+		if err := m.(*Client).requireCapability(placementPolicyCapability, "placement_policy"); err != nil {
+			return err
+		}
+	}
+
+	pairs := d.Get("allowed_address_pairs").([]interface{})
+	if len(pairs) == 0 {
+		return nil
+	}
+	if err := m.(*Client).requireCapability("allowed_address_pairs", "allowed_address_pairs"); err != nil {
+		return err
+	}
+	if !d.NewValueKnown("networks") {
+		return nil
+	}
+
+	attached := map[string]bool{}
+	for _, network := range d.Get("networks").([]interface{}) {
+		attached[network.(string)] = true
+	}
+	// Networks left unset default to the provider's default_networks.
+	if len(attached) == 0 {
+		for _, network := range m.(*Client).defaultNetworks {
+			attached[network] = true
+		}
+	}
+	for i, v := range pairs {
+		network := v.(map[string]interface{})["network"].(string)
+		if network != "" && !attached[network] {
+			return fmt.Errorf("allowed_address_pairs.%d.network: the server is not attached to network '%s'", i, network)
+		}
+	}
+	return nil
+}
+
+// expandServerSecurity converts the security block into its API form, or nil
+// when the block is absent.
+func expandServerSecurity(list []interface{}) *faxter.ServerSecurity {
+	if len(list) == 0 || list[0] == nil {
+		return nil
+	}
+	m := list[0].(map[string]interface{})
+	return &faxter.ServerSecurity{
+		EncryptedLocalDisks: m["encrypted_local_disks"].(bool),
+		ConfidentialVM:      m["confidential_vm"].(bool),
+	}
+}
+
+func flattenServerSecurity(security *faxter.ServerSecurity) []interface{} {
+	return []interface{}{
+		map[string]interface{}{
+			"encrypted_local_disks": security.EncryptedLocalDisks,
+			"confidential_vm":       security.ConfidentialVM,
+		},
+	}
+}
+
+// suppressEquivalentReference ignores differences in case and surrounding
+// whitespace for references the API resolves case-insensitively, such as
+// image and key names.
+func suppressEquivalentReference(k, old, new string, d *schema.ResourceData) bool {
+	return strings.EqualFold(strings.TrimSpace(old), strings.TrimSpace(new))
+}
+
 // Helper function to convert a []interface{} to []string
 func expandStringList(list []interface{}) []string {
 	var result []string
@@ -429,3 +1121,34 @@ func expandStringList(list []interface{}) []string {
 	}
 	return result
 }
+
+func expandStringMap(m map[string]interface{}) map[string]string {
+	if len(m) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(m))
+	for k, v := range m {
+		result[k] = v.(string)
+	}
+	return result
+}
+
+// userDataEncryptionCapability is reported by API versions that accept
+// encrypted user data.
+const userDataEncryptionCapability = "encrypted_user_data"
+
+// encryptCloudInit seals user data with the public key of project.
+func encryptCloudInit(ctx context.Context, c *Client, project string, userData []byte) (*faxter.EncryptedUserData, error) {
+	if err := c.requireCapability(userDataEncryptionCapability, "encrypt_cloud_init"); err != nil {
+		return nil, err
+	}
+	key, err := c.api.GetProjectPublicKey(ctx, project)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the public key of project '%s': %w", project, err)
+	}
+	encrypted, err := faxter.EncryptUserData(key, userData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt cloud_init: %w", err)
+	}
+	return encrypted, nil
+}
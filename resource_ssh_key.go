@@ -1,45 +1,34 @@
 package main
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
 
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
-type SSHKeyCreateRequest struct {
-	Project   string `json:"project,omitempty"`
-	Name      string `json:"name"`
-	PublicKey string `json:"public_key"`
-}
-
-type SSHKeyUpdateRequest struct {
-	Project   string `json:"project,omitempty"`
-	Name      string `json:"name,omitempty"`
-	PublicKey string `json:"public_key,omitempty"`
-}
-
 func resourceSSHKey() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceSSHKeyCreate,
 		ReadContext:   resourceSSHKeyRead,
 		UpdateContext: resourceSSHKeyUpdate,
 		DeleteContext: resourceSSHKeyDelete,
+		CustomizeDiff: customizeDiffProject,
+		Importer: &schema.ResourceImporter{
+			StateContext: importProjectScoped,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"project": {
 				Type:     schema.TypeString,
 				Optional: true,
-				Default:  "default",
+				Computed: true,
 			},
 			"name": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateName,
 			},
 			"public_key": {
 				Type:      schema.TypeString,
@@ -54,33 +43,15 @@ func resourceSSHKeyCreate(ctx context.Context, d *schema.ResourceData, m interfa
 	c := m.(*Client)
 	var diags diag.Diagnostics
 
-	reqData := &SSHKeyCreateRequest{
+	reqData := &faxter.SSHKeyCreateRequest{
 		Project:   d.Get("project").(string),
 		Name:      d.Get("name").(string),
 		PublicKey: d.Get("public_key").(string),
 	}
 
-	bodyBytes, _ := json.Marshal(reqData)
-	req, err := c.newRequest("POST", "/ssh_keys/")
-	if err != nil {
-		return diag.FromErr(err)
-	}
-	req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
-
-	resp, err := c.httpClient.Do(req)
+	resourceResp, err := c.api.CreateSSHKey(ctx, reqData)
 	if err != nil {
-		return diag.FromErr(err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return diag.Errorf("Failed to create SSH key: %s", resp.Status)
-	}
-
-	var resourceResp ResourceResponse
-	err = json.NewDecoder(resp.Body).Decode(&resourceResp)
-	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiag("Failed to create SSH key", err)
 	}
 
 	// Use the 'id' from the resource response as the Terraform ID
@@ -94,27 +65,14 @@ func resourceSSHKeyRead(ctx context.Context, d *schema.ResourceData, m interface
 	c := m.(*Client)
 	var diags diag.Diagnostics
 
-	id := d.Id()
-	path := fmt.Sprintf("/ssh_keys/%s", id)
-	req, err := c.newRequest("GET", path)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode == http.StatusNotFound {
+	_, err := c.api.GetSSHKey(ctx, d.Id())
+	if faxter.IsNotFound(err) {
 		// Key no longer exists
 		d.SetId("")
 		return diags
 	}
-
-	if resp.StatusCode != http.StatusOK {
-		return diag.Errorf("Failed to read SSH key: %s", resp.Status)
+	if err != nil {
+		return apiErrorDiag("Failed to read SSH key", err)
 	}
 
 	// If needed, parse resource again (not strictly necessary if name doesn't change)
@@ -126,34 +84,19 @@ func resourceSSHKeyUpdate(ctx context.Context, d *schema.ResourceData, m interfa
 	c := m.(*Client)
 	var diags diag.Diagnostics
 
-	// Since the ssh_key ID is stored as an integer string, retrieve that
-	id := d.Id()
-	project := d.Get("project").(string)
-	name := d.Get("name").(string)
-	publicKey := d.Get("public_key").(string)
-
-	reqData := &SSHKeyUpdateRequest{
-		Project:   project,
-		Name:      name, // If name is editable
-		PublicKey: publicKey,
+	reqData := &faxter.SSHKeyUpdateRequest{
+		Project:   d.Get("project").(string),
+		Name:      d.Get("name").(string), // If name is editable
+		PublicKey: d.Get("public_key").(string),
 	}
 
-	bodyBytes, _ := json.Marshal(reqData)
-	path := fmt.Sprintf("/ssh_keys/%s", id) // Using the ID as key_name as per previous logic
-	req, err := c.newRequest("PUT", path)
-	if err != nil {
-		return diag.FromErr(err)
+	// Using the ID as key_name as per previous logic
+	err := c.api.UpdateSSHKey(ctx, d.Id(), reqData)
+	if faxter.IsNotFound(err) {
+		return resourceGone(d, "SSH key")
 	}
-
-	req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
-	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return diag.FromErr(err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return diag.Errorf("Failed to update ssh key: %s", resp.Status)
+		return apiErrorDiag("Failed to update ssh key", err)
 	}
 
 	return diags
@@ -163,21 +106,12 @@ func resourceSSHKeyDelete(ctx context.Context, d *schema.ResourceData, m interfa
 	c := m.(*Client)
 	var diags diag.Diagnostics
 
-	id := d.Id()
-	path := fmt.Sprintf("/ssh_keys/%s", id)
-	req, err := c.newRequest("DELETE", path)
-	if err != nil {
-		return diag.FromErr(err)
+	err := c.api.DeleteSSHKey(ctx, d.Id())
+	if faxter.IsNotFound(err) {
+		return resourceGone(d, "SSH key")
 	}
-
-	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return diag.FromErr(err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return diag.Errorf("Failed to delete SSH key: %s", resp.Status)
+		return apiErrorDiag("Failed to delete SSH key", err)
 	}
 
 	d.SetId("")
@@ -2,48 +2,40 @@ package main
 
 import (
   "context"
-  "encoding/json"
-  "fmt"
-  "io"
-  "bytes"
-  "net/http"
-  "net/url"
 
+  "github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
   "github.com/hashicorp/terraform-plugin-sdk/v2/diag"
   "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
-type VolumeCreateRequest struct {
-  Project string `json:"project,omitempty"`
-  Name    string `json:"name"`
-  Storage    int    `json:"storage"`
-}
-
-type VolumeUpdateRequest struct {
-  Project string `json:"project,omitempty"`
-  Storage    int    `json:"storage"`
-}
-
 func resourceVolume() *schema.Resource {
   return &schema.Resource{
     CreateContext: resourceVolumeCreate,
     ReadContext:   resourceVolumeRead,
     UpdateContext: resourceVolumeUpdate,
     DeleteContext: resourceVolumeDelete,
+    CustomizeDiff: customizeDiffProject,
+    Importer: &schema.ResourceImporter{
+      StateContext: importProjectScoped,
+    },
 
     Schema: map[string]*schema.Schema{
       "project": {
         Type:     schema.TypeString,
-        Required: true,
+        Optional: true,
+        Computed: true,
       },
       "name": {
-        Type:     schema.TypeString,
-        Required: true,
+        Type:         schema.TypeString,
+        Required:     true,
+        ValidateFunc: validateName,
       },
       "storage": {
         Type:     schema.TypeInt,
         Required: true,
       },
+      "lock": lockSchema(),
+      "properties_json": propertiesJSONSchema(),
     },
   }
 }
@@ -52,36 +44,28 @@ func resourceVolumeCreate(ctx context.Context, d *schema.ResourceData, m interfa
   c := m.(*Client)
   var diags diag.Diagnostics
 
-  reqData := &VolumeCreateRequest{
+  reqData := &faxter.VolumeCreateRequest{
     Project: d.Get("project").(string),
     Name:    d.Get("name").(string),
     Storage:    d.Get("storage").(int),
   }
 
-  bodyBytes, _ := json.Marshal(reqData)
-  req, err := c.newRequest("POST", "/volumes/")
+  resourceResp, err := c.api.CreateVolume(ctx, reqData)
   if err != nil {
-    return diag.FromErr(err)
+    return apiErrorDiag("Failed to create volume", err)
   }
-  req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
 
-  resp, err := c.httpClient.Do(req)
-  if err != nil {
+  d.SetId(resourceResp.Name)
+  if err := setPropertiesJSON(d, resourceResp.RawProperties); err != nil {
     return diag.FromErr(err)
   }
-  defer resp.Body.Close()
-
-  if resp.StatusCode != http.StatusOK {
-    return diag.Errorf("Failed to create volume: %s", resp.Status)
-  }
 
-  var resourceResp ResourceResponse
-  err = json.NewDecoder(resp.Body).Decode(&resourceResp)
-  if err != nil {
-    return diag.FromErr(err)
+  if d.Get("lock").(bool) {
+    if err := setLock(ctx, c, "volumes", reqData.Project, d.Id(), true); err != nil {
+      return diag.FromErr(err)
+    }
   }
 
-  d.SetId(resourceResp.Name)
   return diags
 }
 
@@ -89,36 +73,23 @@ func resourceVolumeRead(ctx context.Context, d *schema.ResourceData, m interface
   c := m.(*Client)
   var diags diag.Diagnostics
 
-  name := d.Id()
-  project := d.Get("project").(string)
-  path := fmt.Sprintf("/volumes/%s?project_name=%s", url.PathEscape(name), url.QueryEscape(project))
-  req, err := c.newRequest("GET", path)
-  if err != nil {
-    return diag.FromErr(err)
-  }
-
-  resp, err := c.httpClient.Do(req)
-  if err != nil {
-    return diag.FromErr(err)
-  }
-  defer resp.Body.Close()
-
-  if resp.StatusCode == http.StatusNotFound {
+  volume, err := c.api.GetVolume(ctx, d.Get("project").(string), d.Id())
+  if faxter.IsNotFound(err) {
     // Volume not found
     d.SetId("")
     return diags
   }
+  if err != nil {
+    return apiErrorDiag("Failed to read volume", err)
+  }
 
-  if resp.StatusCode != http.StatusOK {
-    return diag.Errorf("Failed to read volume: %s", resp.Status)
+  if err := setPropertiesJSON(d, volume.RawProperties); err != nil {
+    return diag.FromErr(err)
   }
 
-  // If needed, parse response and set any updated fields in state:
-  // var volumeResp ResourceResponse
-  // err = json.NewDecoder(resp.Body).Decode(&volumeResp)
-  // if err == nil {
-  //   // Update any fields if API returns them
-  // }
+  if err := readLock(ctx, c, d, "volumes"); err != nil {
+    return diag.FromErr(err)
+  }
 
   return diags
 }
@@ -130,27 +101,23 @@ func resourceVolumeUpdate(ctx context.Context, d *schema.ResourceData, m interfa
   name := d.Id()
   project := d.Get("project").(string)
 
-  reqData := &VolumeUpdateRequest{
+  reqData := &faxter.VolumeUpdateRequest{
     Project: project,
     Storage:    d.Get("storage").(int),
   }
 
-  bodyBytes, _ := json.Marshal(reqData)
-  path := fmt.Sprintf("/volumes/%s?project_name=%s", url.PathEscape(name), url.QueryEscape(project))
-  req, err := c.newRequest("PUT", path)
-  if err != nil {
-    return diag.FromErr(err)
+  err := c.api.UpdateVolume(ctx, project, name, reqData)
+  if faxter.IsNotFound(err) {
+    return resourceGone(d, "volume")
   }
-
-  req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
-  resp, err := c.httpClient.Do(req)
   if err != nil {
-    return diag.FromErr(err)
+    return apiErrorDiag("Failed to update volume", err)
   }
-  defer resp.Body.Close()
 
-  if resp.StatusCode != http.StatusOK {
-    return diag.Errorf("Failed to update volume: %s", resp.Status)
+  if d.HasChange("lock") {
+    if err := setLock(ctx, c, "volumes", project, name, d.Get("lock").(bool)); err != nil {
+      return diag.FromErr(err)
+    }
   }
 
   // If response returns updated info, parse and update state if needed
@@ -163,20 +130,16 @@ func resourceVolumeDelete(ctx context.Context, d *schema.ResourceData, m interfa
 
   name := d.Id()
   project := d.Get("project").(string)
-  path := fmt.Sprintf("/volumes/%s?project_name=%s", url.PathEscape(name), url.QueryEscape(project))
-  req, err := c.newRequest("DELETE", path)
-  if err != nil {
+  if err := releaseLock(ctx, c, "volumes", project, name); err != nil {
     return diag.FromErr(err)
   }
 
-  resp, err := c.httpClient.Do(req)
-  if err != nil {
-    return diag.FromErr(err)
+  err := c.api.DeleteVolume(ctx, project, name)
+  if faxter.IsNotFound(err) {
+    return resourceGone(d, "volume")
   }
-  defer resp.Body.Close()
-
-  if resp.StatusCode != http.StatusOK {
-    return diag.Errorf("Failed to delete volume: %s", resp.Status)
+  if err != nil {
+    return apiErrorDiag("Failed to delete volume", err)
   }
 
   d.SetId("")
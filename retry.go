@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// idempotencyCapability is reported by API versions that recognise a
+// repeated POST by its Idempotency-Key.
+const idempotencyCapability = "idempotency_keys"
+
+// retryTransport retries requests that fail before a response is received
+// because of a transient network problem: a reset or refused connection, a
+// timeout or a DNS failure. A create without an Idempotency-Key is only
+// retried when it can't have reached the API. Requests answered 429 Too Many Requests are
+// retried too, as are those answered 502 Bad Gateway or 503 Service
+// Unavailable when sending them again is safe: idempotent methods, and
+// creates carrying an Idempotency-Key. Waits start at waitMin and double up
+// to waitMax, but are never shorter than the response's Retry-After.
+type retryTransport struct {
+	maxRetries int
+	waitMin    time.Duration
+	waitMax    time.Duration
+	base       http.RoundTripper
+
+	// idempotencyKeys adds an Idempotency-Key to POST requests, so that the
+	// API ignores a repeated create it already carried out. Only set for
+	// APIs that deduplicate on the key.
+	idempotencyKeys bool
+}
+
+func newRetryTransport(maxRetries int, waitMin, waitMax time.Duration, base http.RoundTripper) *retryTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &retryTransport{
+		maxRetries: maxRetries,
+		waitMin:    waitMin,
+		waitMax:    waitMax,
+		base:       base,
+	}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Keep the body so it can be sent again on each attempt.
+	getBody, length, err := replayableBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := req.Context()
+	idempotencyKey := req.Header.Get("Idempotency-Key")
+	if idempotencyKey == "" && t.idempotencyKeys && req.Method == http.MethodPost {
+		idempotencyKey = newIdempotencyKey()
+	}
+
+	wait := t.waitMin
+	for attempt := 0; ; attempt++ {
+		try, err := withBody(req, getBody, length)
+		if err != nil {
+			return nil, err
+		}
+		if idempotencyKey != "" {
+			try.Header.Set("Idempotency-Key", idempotencyKey)
+		}
+
+		resp, err := t.base.RoundTrip(try)
+		if attempt >= t.maxRetries {
+			return resp, err
+		}
+
+		delay := wait
+		fields := map[string]interface{}{
+			"method":  req.Method,
+			"url":     req.URL.String(),
+			"attempt": attempt + 1,
+		}
+		switch {
+		case err != nil:
+			if !isTransientNetworkError(ctx, err, req.Method, idempotencyKey != "") {
+				return nil, err
+			}
+			fields["error"] = err.Error()
+			fields["wait"] = delay.String()
+			tflog.Debug(ctx, "Transient network error, retrying request", fields)
+		case isRetryableStatus(resp.StatusCode, req.Method, idempotencyKey != ""):
+			if retryAfter := parseRetryAfter(resp.Header.Get("Retry-After")); retryAfter > delay {
+				delay = retryAfter
+			}
+			// Let the connection be reused for the next attempt.
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+			fields["status"] = resp.StatusCode
+			fields["wait"] = delay.String()
+			tflog.Debug(ctx, "API is rate limiting or unavailable, retrying request", fields)
+		default:
+			return resp, nil
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		wait *= 2
+		if wait > t.waitMax {
+			wait = t.waitMax
+		}
+	}
+}
+
+// isRetryableStatus reports whether a response with status may be retried.
+// A 429 means the request was turned away unprocessed, so any method can be
+// sent again. After a 502 or 503 a create may have been carried out, so it
+// is only sent again when the API can recognise it as a repeat.
+func isRetryableStatus(status int, method string, hasIdempotencyKey bool) bool {
+	switch status {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return isIdempotent(method) || hasIdempotencyKey
+	}
+	return false
+}
+
+// isIdempotent reports whether sending a request with method twice has the
+// same effect as sending it once.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// parseRetryAfter returns the wait requested by a Retry-After header, given
+// either in seconds or as an HTTP date, or 0 if there is none.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(at); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
+
+// newIdempotencyKey returns a random key identifying one logical request
+// across its attempts.
+func newIdempotencyKey() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// isTransientNetworkError reports whether err is a network failure worth
+// retrying. Cancellation of the request's own context is not. A reset,
+// timeout or early EOF may come after the API carried out the request, so a
+// create is only retried after those when the API can recognise it as a
+// repeat; a DNS failure or refused connection means it was never sent.
+func isTransientNetworkError(ctx context.Context, err error, method string, hasIdempotencyKey bool) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	if !isIdempotent(method) && !hasIdempotencyKey {
+		return false
+	}
+
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// TestRetrySignsEachAttempt checks that a request retried after a
+// Retry-After is signed again rather than resent with the first attempt's
+// timestamp and signature.
+func TestRetrySignsEachAttempt(t *testing.T) {
+	var mu sync.Mutex
+	var timestamps, signatures []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/volumes/") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		mu.Lock()
+		timestamps = append(timestamps, r.Header.Get(headerTimestamp))
+		signatures = append(signatures, r.Header.Get(headerSignature))
+		attempt := len(timestamps)
+		mu.Unlock()
+
+		if attempt == 1 {
+			// The timestamp has a resolution of a second, so make the
+			// retry wait for one.
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name":"data","status":"available","properties":{}}`))
+	}))
+	defer srv.Close()
+
+	p := Provider()
+	diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"base_url":                    srv.URL,
+		"signing_key":                 "key",
+		"signing_secret":              "secret",
+		"max_retries":                 1,
+		"retry_wait_min":              "10ms",
+		"skip_credentials_validation": true,
+	}))
+	if diags.HasError() {
+		t.Fatalf("configuring the provider: %v", diags)
+	}
+
+	if _, err := p.Meta().(*Client).api.GetVolume(context.Background(), "acme", "data"); err != nil {
+		t.Fatalf("GetVolume: %s", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(timestamps) != 2 {
+		t.Fatalf("got %d attempts, want 2", len(timestamps))
+	}
+	if timestamps[0] == "" || timestamps[0] == timestamps[1] {
+		t.Errorf("retry was sent with timestamp %q, first attempt with %q; want a new one", timestamps[1], timestamps[0])
+	}
+	if signatures[0] == "" || signatures[0] == signatures[1] {
+		t.Errorf("retry was sent with the first attempt's signature %q", signatures[1])
+	}
+}
+
+// TestRetryTimedOutCreateSentOnce checks that a create without an
+// Idempotency-Key isn't sent again after timing out, since the API may have
+// carried it out.
+func TestRetryTimedOutCreateSentOnce(t *testing.T) {
+	var mu sync.Mutex
+	posts := 0
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/volumes/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		mu.Lock()
+		posts++
+		mu.Unlock()
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	p := Provider()
+	diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"base_url":                    srv.URL,
+		"token":                       "token",
+		"max_retries":                 3,
+		"retry_wait_min":              "10ms",
+		"request_timeout":             "100ms",
+		"skip_credentials_validation": true,
+	}))
+	if diags.HasError() {
+		t.Fatalf("configuring the provider: %v", diags)
+	}
+
+	_, err := p.Meta().(*Client).api.CreateVolume(context.Background(), &faxter.VolumeCreateRequest{
+		Project: "acme",
+		Name:    "data",
+		Storage: 10,
+	})
+	if err == nil {
+		t.Fatal("CreateVolume succeeded against a server that never answers")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if posts != 1 {
+		t.Errorf("create was sent %d times, want 1", posts)
+	}
+}
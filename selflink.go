@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// selfLinkPaths maps each resource to the API path of the object it manages,
+// relative to base_url.
+var selfLinkPaths = map[string]func(d *schema.ResourceData) string{
+	"faxter_project": func(d *schema.ResourceData) string {
+		return "/projects/" + url.PathEscape(d.Id())
+	},
+	"faxter_server":           projectObjectLink("servers"),
+	"faxter_network":          projectObjectLink("networks"),
+	"faxter_router":           projectObjectLink("routers"),
+	"faxter_volume":           projectObjectLink("volumes"),
+	"faxter_security_group":   projectObjectLink("security_groups"),
+	"faxter_loadbalancer":     projectObjectLink("loadbalancers"),
+	"faxter_lb_profile":       projectObjectLink("lb_profiles"),
+	"faxter_gateway_service":  projectObjectLink("gateway_services"),
+	"faxter_qos_policy":       projectObjectLink("qos_policies"),
+	"faxter_placement_policy": projectObjectLink("placement_policies"),
+	"faxter_quota_request":    projectObjectLink("quota_requests"),
+	"faxter_reverse_dns":      projectObjectLink("reverse_dns"),
+	"faxter_ssh_key": func(d *schema.ResourceData) string {
+		return "/ssh_keys/" + url.PathEscape(d.Id())
+	},
+	"faxter_security_group_rule": func(d *schema.ResourceData) string {
+		securityGroup, ruleID, _ := strings.Cut(d.Id(), "/")
+		return securityGroupRulePath(d.Get("project").(string), securityGroup, ruleID)
+	},
+	"faxter_billing_alert": func(d *schema.ResourceData) string {
+		return billingAlertPath(d.Get("project").(string), d.Id())
+	},
+	"faxter_host_aggregate": func(d *schema.ResourceData) string {
+		return hostAggregatePath(d.Id())
+	},
+	"faxter_object_storage_bucket_policy": func(d *schema.ResourceData) string {
+		return bucketPolicyPath(d.Get("project").(string), d.Id())
+	},
+	"faxter_image_member": func(d *schema.ResourceData) string {
+		return fmt.Sprintf("/images/%s/members/%s?project_name=%s", url.PathEscape(d.Get("image").(string)), url.PathEscape(d.Get("member_project").(string)), url.QueryEscape(d.Get("project").(string)))
+	},
+	"faxter_resource_lock": func(d *schema.ResourceData) string {
+		return fmt.Sprintf("/%s/%s/lock?project_name=%s", lockCollections[d.Get("resource_type").(string)], url.PathEscape(d.Get("resource_name").(string)), url.QueryEscape(d.Get("project").(string)))
+	},
+	"faxter_script": func(d *schema.ResourceData) string {
+		server, execID, _ := strings.Cut(d.Id(), "/")
+		return fmt.Sprintf("/servers/%s/exec/%s?project_name=%s", url.PathEscape(server), url.PathEscape(execID), url.QueryEscape(d.Get("project").(string)))
+	},
+}
+
+func projectObjectLink(collection string) func(d *schema.ResourceData) string {
+	return func(d *schema.ResourceData) string {
+		return faxter.ObjectPath(collection, d.Get("project").(string), d.Id())
+	}
+}
+
+// withSelfLink adds the computed self_link attribute, holding the full API
+// URL of the managed object, and keeps it current after every create, read
+// and update. Every resource must have an entry in selfLinkPaths.
+func withSelfLink(name string, r *schema.Resource) {
+	path, ok := selfLinkPaths[name]
+	if !ok {
+		panic(fmt.Sprintf("no self_link path for resource %s", name))
+	}
+	r.Schema["self_link"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "Full API URL of the object, for use by external tooling.",
+	}
+	r.CreateContext = setSelfLink(path, r.CreateContext)
+	r.ReadContext = setSelfLink(path, r.ReadContext)
+	r.UpdateContext = setSelfLink(path, r.UpdateContext)
+}
+
+func setSelfLink[F ~func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics](path func(*schema.ResourceData) string, f F) F {
+	if f == nil {
+		return nil
+	}
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		diags := f(ctx, d, m)
+		if d.Id() == "" {
+			return diags
+		}
+		if err := d.Set("self_link", m.(*Client).rest.BaseURL+path(d)); err != nil {
+			return append(diags, diag.Errorf("Error setting self_link: %s", err)...)
+		}
+		return diags
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// withStop makes a resource's CRUD functions stop as soon as Terraform asks
+// the provider to, e.g. on Ctrl-C, rather than waiting out their polling
+// loops. The SDK doesn't cancel an operation's context itself, so the
+// context is cancelled along with the client's stop context, which takes
+// in-flight requests and waits down with it.
+func withStop(r *schema.Resource) {
+	r.CreateContext = stopOnInterrupt(r.CreateContext)
+	r.ReadContext = stopOnInterrupt(r.ReadContext)
+	r.UpdateContext = stopOnInterrupt(r.UpdateContext)
+	r.DeleteContext = stopOnInterrupt(r.DeleteContext)
+}
+
+func stopOnInterrupt[F ~func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics](f F) F {
+	if f == nil {
+		return nil
+	}
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		c := m.(*Client)
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		defer context.AfterFunc(c.stop, cancel)()
+
+		diags := f(ctx, d, m)
+		if c.stop.Err() != nil && diags.HasError() {
+			// Whatever the operation recorded before it was cancelled,
+			// including the ID of an object it had created, is saved.
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "Operation interrupted",
+				Detail:   "Terraform asked the provider to stop, so the operation was cancelled. The state records how far it got; run terraform apply again to finish it.",
+			})
+		}
+		return diags
+	}
+}
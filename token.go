@@ -0,0 +1,178 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+)
+
+// tokenRefreshTransport keeps long applies authenticated when the bearer
+// token expires mid-run. A request rejected with 401 triggers one exchange
+// of the refresh token (or OIDC token) for a new bearer token, after which
+// the request is replayed with it. Concurrent requests that fail with the
+// same expired token share a single exchange.
+type tokenRefreshTransport struct {
+	baseURL string
+	base    http.RoundTripper
+
+	// Sent with the exchange, as with every other API request; see
+	// extra_headers.
+	headers http.Header
+
+	// When set, the bearer token is obtained by exchanging the OIDC token
+	// in this file instead of a refresh token.
+	oidcTokenFile string
+	oidcAudience  string
+
+	mu           sync.Mutex
+	token        string
+	refreshToken string
+}
+
+func newTokenRefreshTransport(baseURL, token, refreshToken string, headers http.Header, base http.RoundTripper) *tokenRefreshTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &tokenRefreshTransport{
+		baseURL:      baseURL,
+		base:         base,
+		headers:      headers,
+		token:        token,
+		refreshToken: refreshToken,
+	}
+}
+
+// newOIDCTransport returns a transport that authenticates by exchanging the
+// workload identity token in tokenFile, e.g. one issued to a CI job, for a
+// bearer token.
+func newOIDCTransport(baseURL, tokenFile, audience string, headers http.Header, base http.RoundTripper) *tokenRefreshTransport {
+	t := newTokenRefreshTransport(baseURL, "", "", headers, base)
+	t.oidcTokenFile = tokenFile
+	t.oidcAudience = audience
+	return t
+}
+
+func (t *tokenRefreshTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Keep the body so the request can be replayed after a refresh.
+	getBody, length, err := replayableBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := t.currentToken(req.Context(), "")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.send(req, getBody, length, token)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	resp.Body.Close()
+
+	token, err = t.currentToken(req.Context(), token)
+	if err != nil {
+		return nil, err
+	}
+	return t.send(req, getBody, length, token)
+}
+
+func (t *tokenRefreshTransport) send(req *http.Request, getBody func() (io.ReadCloser, error), length int64, token string) (*http.Response, error) {
+	authed, err := withBody(req, getBody, length)
+	if err != nil {
+		return nil, err
+	}
+	authed.Header.Set("Authorization", "Bearer "+token)
+	return t.base.RoundTrip(authed)
+}
+
+// currentToken returns the bearer token to use. If the token is missing or
+// equals rejected, it is exchanged for a new one first; a token that another
+// request has already refreshed is returned as is.
+func (t *tokenRefreshTransport) currentToken(ctx context.Context, rejected string) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token != "" && t.token != rejected {
+		return t.token, nil
+	}
+
+	path, exchange, err := t.exchangeRequest()
+	if err != nil {
+		return "", err
+	}
+	bodyBytes, _ := json.Marshal(exchange)
+	req, err := http.NewRequestWithContext(ctx, "POST", t.baseURL+path, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return "", err
+	}
+	for k, v := range t.headers {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to refresh token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to refresh token: %w", faxter.NewError(resp))
+	}
+
+	var refreshed faxter.TokenRefreshResponse
+	if err := faxter.DecodeResponse(resp, &refreshed); err != nil {
+		return "", fmt.Errorf("failed to decode token refresh response: %w", err)
+	}
+	if refreshed.AccessToken == "" {
+		return "", fmt.Errorf("failed to refresh token: no access_token in response")
+	}
+
+	t.token = refreshed.AccessToken
+	if refreshed.RefreshToken != "" {
+		t.refreshToken = refreshed.RefreshToken
+	}
+	return t.token, nil
+}
+
+// exchangeRequest returns the path and body of the request for a new bearer
+// token. The OIDC token is read afresh each time because CI systems replace
+// it with a new one during long jobs.
+func (t *tokenRefreshTransport) exchangeRequest() (string, interface{}, error) {
+	if t.oidcTokenFile == "" {
+		return faxter.RefreshTokenPath, &faxter.TokenRefreshRequest{RefreshToken: t.refreshToken}, nil
+	}
+
+	raw, err := os.ReadFile(t.oidcTokenFile)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read OIDC token: %w", err)
+	}
+	token := strings.TrimSpace(string(raw))
+	if token == "" {
+		return "", nil, fmt.Errorf("failed to read OIDC token: %s is empty", t.oidcTokenFile)
+	}
+	return faxter.OIDCExchangePath, &faxter.OIDCExchangeRequest{Token: token, Audience: t.oidcAudience}, nil
+}
+
+// validateCredentials confirms the API accepts the provider's credentials, so
+// that a bad token fails the run up front rather than with a bare 401 from
+// the first resource.
+func validateCredentials(ctx context.Context, c *Client) error {
+	err := c.rest.CheckCredentials(ctx)
+	if faxter.IsUnauthorized(err) {
+		return fmt.Errorf("the Faxter API rejected the provider's credentials: token invalid or expired (%s). Check token, refresh_token, oidc_token_file or signing_key and signing_secret", err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to validate credentials: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies the provider's spans in a tracing backend.
+const tracerName = "github.com/ahmadmicro/terraform-provider-faxter"
+
+// setupTracing exports spans over OTLP/HTTP when OTEL_EXPORTER_OTLP_ENDPOINT
+// or OTEL_EXPORTER_OTLP_TRACES_ENDPOINT is set; the exporter reads the rest of
+// its configuration, e.g. headers and TLS, from the standard OTEL_* variables.
+// Without an endpoint the global tracer stays a no-op and spans cost nothing.
+// The returned function flushes pending spans and must be called on exit.
+func setupTracing(ctx context.Context) (func(context.Context) error, error) {
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", "terraform-provider-faxter"),
+		attribute.String("service.version", version),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe trace resource: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return tp.Shutdown, nil
+}
+
+func tracer() trace.Tracer {
+	return otel.Tracer(tracerName, trace.WithInstrumentationVersion(version))
+}
+
+// withTracing wraps a resource's CRUD functions in a span named after the
+// resource and operation, e.g. "faxter_server create", so the API calls made
+// by the operation appear as its children. Spans are only started when the
+// provider's tracing setting is on.
+func withTracing(name string, r *schema.Resource) {
+	r.CreateContext = traceOperation(name, "create", r.CreateContext)
+	r.ReadContext = traceOperation(name, "read", r.ReadContext)
+	r.UpdateContext = traceOperation(name, "update", r.UpdateContext)
+	r.DeleteContext = traceOperation(name, "delete", r.DeleteContext)
+}
+
+func traceOperation[F ~func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics](name, operation string, f F) F {
+	if f == nil {
+		return nil
+	}
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		if !m.(*Client).tracing {
+			return f(ctx, d, m)
+		}
+
+		start := time.Now()
+		ctx, span := tracer().Start(ctx, name+" "+operation, trace.WithAttributes(
+			attribute.String("faxter.resource_type", name),
+			attribute.String("faxter.operation", operation),
+		))
+		defer span.End()
+
+		diags := f(ctx, d, m)
+
+		// Create only learns the ID once the object exists.
+		span.SetAttributes(
+			attribute.String("faxter.resource_id", d.Id()),
+			attribute.Int64("faxter.duration_ms", time.Since(start).Milliseconds()),
+		)
+		for _, diagnostic := range diags {
+			if diagnostic.Severity == diag.Error {
+				span.SetStatus(codes.Error, diagnostic.Summary)
+				break
+			}
+		}
+		return diags
+	}
+}
+
+// tracingTransport records a client span for every API call, retries
+// included, and propagates the trace context to the API in the traceparent
+// header. Spans are named after the call's route, e.g.
+// "GET /servers/{name}", and carry the name of the object it addressed.
+type tracingTransport struct {
+	base http.RoundTripper
+}
+
+func newTracingTransport(base http.RoundTripper) *tracingTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &tracingTransport{base: base}
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	route, name := apiRoute(req.URL.Path)
+	operation := req.Method + " " + route
+	attrs := []attribute.KeyValue{
+		attribute.String("http.request.method", req.Method),
+		attribute.String("http.route", route),
+		attribute.String("server.address", req.URL.Hostname()),
+		attribute.String("url.path", req.URL.Path),
+		attribute.String("faxter.operation", operation),
+	}
+	if name != "" {
+		attrs = append(attrs, attribute.String("faxter.resource_name", name))
+	}
+	if project := req.URL.Query().Get("project_name"); project != "" {
+		attrs = append(attrs, attribute.String("faxter.project", project))
+	}
+
+	start := time.Now()
+	ctx, span := tracer().Start(req.Context(), operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	defer span.End()
+
+	if !span.IsRecording() {
+		return t.base.RoundTrip(req)
+	}
+
+	// A RoundTripper must not modify the caller's request.
+	req = req.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := t.base.RoundTrip(req)
+	// The time to the response headers; reading the body is up to the caller.
+	span.SetAttributes(attribute.Int64("faxter.duration_ms", time.Since(start).Milliseconds()))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= 400 {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	return resp, nil
+}
+
+// apiRoute returns the route of an API path, with the object names that
+// alternate with collection names replaced by placeholders, and the name of
+// the object addressed, if any: "/servers/web/exec/42" gives
+// "/servers/{name}/exec/{id}" and "web".
+func apiRoute(path string) (route, name string) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 1; i < len(segments); i += 2 {
+		if i == 1 {
+			name, _ = url.PathUnescape(segments[i])
+			segments[i] = "{name}"
+		} else {
+			segments[i] = "{id}"
+		}
+	}
+	return "/" + strings.Join(segments, "/"), name
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+)
+
+// transportConfig holds the provider settings applied to the HTTP transport
+// underneath every API request.
+type transportConfig struct {
+	caCertFile         string
+	clientCertFile     string
+	clientKeyFile      string
+	insecureSkipVerify bool
+	proxyURL           string
+
+	// Connection pool; zero values keep the client's defaults.
+	maxIdleConnsPerHost int
+	idleConnTimeout     time.Duration
+}
+
+// build returns a transport for the API with the configured proxy, TLS and
+// connection pool settings. It negotiates HTTP/2 and keeps idle connections
+// for reuse, see faxter.NewTransport. Without proxy_url, HTTP_PROXY, HTTPS_PROXY and NO_PROXY from
+// the environment apply. A CA bundle is added to the system trust store
+// rather than replacing it. A client certificate, when configured, is
+// presented to APIs that require mutual TLS.
+func (tc transportConfig) build() (*http.Transport, error) {
+	transport := faxter.NewTransport()
+	if tc.maxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = tc.maxIdleConnsPerHost
+	}
+	if tc.idleConnTimeout > 0 {
+		transport.IdleConnTimeout = tc.idleConnTimeout
+	}
+	transport.Proxy = http.ProxyFromEnvironment
+	if tc.proxyURL != "" {
+		proxy, err := url.Parse(tc.proxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy_url: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: tc.insecureSkipVerify,
+	}
+
+	if tc.caCertFile != "" {
+		pem, err := os.ReadFile(tc.caCertFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ca_cert_file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("ca_cert_file %s contains no PEM certificates", tc.caCertFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if tc.clientCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(tc.clientCertFile, tc.clientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+// timeoutTransport bounds each attempt of a request, including reading the
+// response body, by deriving a deadline from the request's context. Sitting
+// below the retry transport, a hung attempt times out and can be retried
+// rather than stalling the whole run.
+type timeoutTransport struct {
+	timeout time.Duration
+	base    http.RoundTripper
+}
+
+func newTimeoutTransport(timeout time.Duration, base http.RoundTripper) *timeoutTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &timeoutTransport{
+		timeout: timeout,
+		base:    base,
+	}
+}
+
+func (t *timeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnClose releases a request's timeout once its body has been closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// replayableBody prepares the body of req to be sent more than once, by the
+// transports that retry, re-authenticate or sign requests. A body that can
+// be reopened with GetBody, such as a streamed upload, is never read into
+// memory; any other body is buffered. It returns a nil func for a request
+// without a body.
+func replayableBody(req *http.Request) (func() (io.ReadCloser, error), int64, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, 0, nil
+	}
+	if req.GetBody != nil {
+		req.Body.Close()
+		return req.GetBody, req.ContentLength, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, 0, err
+	}
+	return func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}, int64(len(body)), nil
+}
+
+// withBody returns a copy of req, as a RoundTripper must not modify the
+// caller's request, whose body is freshly opened by getBody.
+func withBody(req *http.Request, getBody func() (io.ReadCloser, error), length int64) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if getBody == nil {
+		return clone, nil
+	}
+	body, err := getBody()
+	if err != nil {
+		return nil, err
+	}
+	clone.Body = body
+	clone.GetBody = getBody
+	clone.ContentLength = length
+	return clone, nil
+}
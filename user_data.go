@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// maxUserData is the most user data, base64-encoded, the API accepts for
+	// a server.
+	maxUserData = 65535
+
+	// userDataGzipThreshold is the size above which user data is sent
+	// gzip-compressed. cloud-init recognises and unpacks gzip by itself, so
+	// compression only costs a little CPU at boot, and it lets scripts that
+	// would exceed the limit as text fit.
+	userDataGzipThreshold = 16 << 10
+)
+
+// gzipMagic starts every gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// serverUserData returns the user data configured for a server, from
+// cloud_init or user_data_base64, and whether it came as base64.
+func serverUserData(get func(string) interface{}) ([]byte, bool, error) {
+	if encoded := get("user_data_base64").(string); encoded != "" {
+		data, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, true, fmt.Errorf("user_data_base64: %w", err)
+		}
+		return data, true, nil
+	}
+	return []byte(get("cloud_init").(string)), false, nil
+}
+
+// compressUserData gzips data above userDataGzipThreshold that isn't
+// compressed already, and reports whether it did.
+func compressUserData(data []byte) ([]byte, bool, error) {
+	if len(data) <= userDataGzipThreshold || bytes.HasPrefix(data, gzipMagic) {
+		return data, false, nil
+	}
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, false, err
+	}
+	if _, err := zw.Write(data); err != nil {
+		return nil, false, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, false, err
+	}
+	return buf.Bytes(), true, nil
+}
+
+// checkUserDataSize fails for user data that is still over the API's limit
+// once compressed and encoded.
+func checkUserDataSize(data []byte, compressed bool) error {
+	size := base64.StdEncoding.EncodedLen(len(data))
+	if size <= maxUserData {
+		return nil
+	}
+	if compressed {
+		return fmt.Errorf("user data is %d bytes base64-encoded even after gzip compression, over the API's limit of %d", size, maxUserData)
+	}
+	return fmt.Errorf("user data is %d bytes base64-encoded, over the API's limit of %d", size, maxUserData)
+}
+
+// setServerUserData puts the configured user data into req. Plain cloud_init
+// small enough to send as it is goes in CloudInit as before; anything
+// compressed or given as base64 goes in UserData; with encrypt_cloud_init it
+// is sealed into EncryptedCloudInit instead.
+func setServerUserData(ctx context.Context, c *Client, d *schema.ResourceData, req *faxter.ServerCreateRequest) error {
+	data, binary, err := serverUserData(d.Get)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	data, compressed, err := compressUserData(data)
+	if err != nil {
+		return fmt.Errorf("failed to compress user data: %w", err)
+	}
+	if err := checkUserDataSize(data, compressed); err != nil {
+		return err
+	}
+
+	switch {
+	case d.Get("encrypt_cloud_init").(bool):
+		encrypted, err := encryptCloudInit(ctx, c, req.Project, data)
+		if err != nil {
+			return err
+		}
+		req.EncryptedCloudInit = encrypted
+	case binary || compressed:
+		req.UserData = base64.StdEncoding.EncodeToString(data)
+	default:
+		req.CloudInit = string(data)
+	}
+	return nil
+}
+
+// customizeDiffUserData checks at plan time that the user data of a new
+// server decodes and fits the API's limit, so that an oversized rendered
+// template fails the plan rather than the apply.
+func customizeDiffUserData(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() != "" || !d.NewValueKnown("cloud_init") || !d.NewValueKnown("user_data_base64") {
+		return nil
+	}
+	data, _, err := serverUserData(d.Get)
+	if err != nil {
+		return err
+	}
+	data, compressed, err := compressUserData(data)
+	if err != nil {
+		return err
+	}
+	return checkUserDataSize(data, compressed)
+}
+
+// trailingSpace matches the whitespace at the end of each line.
+var trailingSpace = regexp.MustCompile(`(?m)[ \t]+$`)
+
+// cloudInitHash matches what hashCloudInit stores.
+var cloudInitHash = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// hashCloudInit is the StateFunc of cloud_init: state keeps a SHA-256 of
+// the content rather than the content, which keeps large scripts out of
+// plans, and the content is normalised first, so that line endings and
+// trailing whitespace don't count as a change.
+func hashCloudInit(v interface{}) string {
+	s := v.(string)
+	if s == "" {
+		return ""
+	}
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.TrimRight(trailingSpace.ReplaceAllString(s, ""), "\n")
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// stateCloudInit returns the hash of a cloud_init value, which may already
+// be one; servers created by earlier versions of the provider hold the
+// content in state in full.
+func stateCloudInit(old string) string {
+	if cloudInitHash.MatchString(old) {
+		return old
+	}
+	return hashCloudInit(old)
+}
+
+// suppressEquivalentCloudInit ignores the change from content stored in
+// full to the hash of the same content.
+func suppressEquivalentCloudInit(k, old, new string, d *schema.ResourceData) bool {
+	return stateCloudInit(old) == stateCloudInit(new)
+}
+
+// customizeDiffReplaceOnUserDataChange replaces a server whose user data
+// changed when replace_on_cloud_init_change is set. User data only takes
+// effect at first boot, so otherwise the change is recorded and nothing
+// more.
+func customizeDiffReplaceOnUserDataChange(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" || !d.Get("replace_on_cloud_init_change").(bool) {
+		return nil
+	}
+	// HasChange doesn't apply the DiffSuppressFunc.
+	if old, new := d.GetChange("cloud_init"); d.HasChange("cloud_init") && !suppressEquivalentCloudInit("cloud_init", old.(string), new.(string), nil) {
+		if err := d.ForceNew("cloud_init"); err != nil {
+			return err
+		}
+	}
+	if d.HasChange("user_data_base64") {
+		return d.ForceNew("user_data_base64")
+	}
+	return nil
+}
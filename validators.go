@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// maxNameLength is the longest resource name the API accepts.
+const maxNameLength = 63
+
+var validNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// validateName enforces the API's naming rules on a resource name so that
+// violations are reported at plan time rather than as a generic 422 on apply.
+func validateName(v interface{}, k string) (ws []string, errs []error) {
+	name, ok := v.(string)
+	if !ok {
+		errs = append(errs, fmt.Errorf("expected %q to be a string", k))
+		return
+	}
+
+	if name == "" {
+		errs = append(errs, fmt.Errorf("%q must not be empty", k))
+		return
+	}
+	if len(name) > maxNameLength {
+		errs = append(errs, fmt.Errorf("%q must be at most %d characters, got %d: %q", k, maxNameLength, len(name), name))
+	}
+	if strings.IndexFunc(name, unicode.IsSpace) >= 0 {
+		errs = append(errs, fmt.Errorf("%q must not contain whitespace, got %q", k, name))
+	}
+	if strings.IndexFunc(name, unicode.IsUpper) >= 0 {
+		errs = append(errs, fmt.Errorf("%q must be lowercase, got %q (try %q)", k, name, strings.ToLower(name)))
+	}
+	if len(errs) == 0 && !validNameRegexp.MatchString(name) {
+		errs = append(errs, fmt.Errorf("%q must start with a letter or digit and contain only lowercase letters, digits, '-' and '_', got %q", k, name))
+	}
+	return
+}
+
+// ptrRecordRegexp matches a fully qualified hostname, with an optional
+// trailing dot, as accepted for reverse DNS records.
+var ptrRecordRegexp = regexp.MustCompile(`^([a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?\.)+[a-zA-Z]{2,63}\.?$`)
+
+// validateDuration checks that a string is a positive Go duration such as "5m".
+func validateDuration(v interface{}, k string) (ws []string, errs []error) {
+	value, ok := v.(string)
+	if !ok {
+		errs = append(errs, fmt.Errorf("expected %q to be a string", k))
+		return
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("%q must be a duration such as 30s, 5m or 1h, got %q", k, value))
+		return
+	}
+	if duration <= 0 {
+		errs = append(errs, fmt.Errorf("%q must be positive, got %q", k, value))
+	}
+	return
+}
+
+// headerNameRegexp matches a valid HTTP header field name.
+var headerNameRegexp = regexp.MustCompile("^[!#$%&'*+.^_`|~0-9A-Za-z-]+$")
+
+// validateExtraHeaders checks that extra_headers holds valid header names and
+// does not try to replace the headers the provider sets itself.
+func validateExtraHeaders(v interface{}, k string) (ws []string, errs []error) {
+	headers, ok := v.(map[string]interface{})
+	if !ok {
+		errs = append(errs, fmt.Errorf("expected %q to be a map", k))
+		return
+	}
+
+	for name := range headers {
+		if !headerNameRegexp.MatchString(name) {
+			errs = append(errs, fmt.Errorf("%q contains an invalid header name %q", k, name))
+			continue
+		}
+		switch http.CanonicalHeaderKey(name) {
+		case "Authorization", "Content-Type", "Content-Length", "Host", "User-Agent":
+			errs = append(errs, fmt.Errorf("%q cannot set %s; it is managed by the provider", k, name))
+		}
+	}
+	return
+}
+
+// validateAPIVersion checks that a string is an API version such as "2.3".
+func validateAPIVersion(v interface{}, k string) (ws []string, errs []error) {
+	value, ok := v.(string)
+	if !ok {
+		errs = append(errs, fmt.Errorf("expected %q to be a string", k))
+		return
+	}
+
+	if _, err := parseAPIVersion(value); err != nil {
+		errs = append(errs, fmt.Errorf("%q: %s", k, err))
+	}
+	return
+}
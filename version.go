@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// minimumAPIVersion is the oldest API release this provider works with.
+const minimumAPIVersion = "1.0"
+
+// negotiateAPIVersion asks the API for its version and capabilities and
+// checks it against the provider's minimum and the configured api_version
+// pin. An API without the version endpoint is assumed compatible unless a
+// version is pinned.
+func negotiateAPIVersion(ctx context.Context, c *Client, pinned string) error {
+	resp, err := c.rest.GetVersion(ctx)
+	if faxter.IsNotFound(err) {
+		if pinned != "" {
+			return fmt.Errorf("api_version is set to %s, but the API does not report its version", pinned)
+		}
+		tflog.Debug(ctx, "API does not report its version; assuming all features are available")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to query API version: %w", err)
+	}
+
+	server, err := parseAPIVersion(resp.Version)
+	if err != nil {
+		return fmt.Errorf("the API reported an invalid version: %w", err)
+	}
+	minimum, _ := parseAPIVersion(minimumAPIVersion)
+	if server.less(minimum) {
+		return fmt.Errorf("the API runs version %s, but this provider requires at least %s", resp.Version, minimumAPIVersion)
+	}
+	if pinned != "" {
+		want, err := parseAPIVersion(pinned)
+		if err != nil {
+			return fmt.Errorf("invalid api_version: %w", err)
+		}
+		if server.major != want.major || server.less(want) {
+			return fmt.Errorf("api_version is pinned to %s, but the API runs incompatible version %s", pinned, resp.Version)
+		}
+	}
+
+	c.apiVersion = resp.Version
+	c.capabilities = make(map[string]bool, len(resp.Capabilities))
+	for _, capability := range resp.Capabilities {
+		c.capabilities[capability] = true
+	}
+	tflog.Debug(ctx, "Negotiated API version", map[string]interface{}{
+		"version":      resp.Version,
+		"capabilities": resp.Capabilities,
+	})
+	return nil
+}
+
+// supports reports whether the API offers an optional feature. When the API
+// did not report its capabilities, every feature is assumed available and
+// the API itself rejects what it does not understand.
+func (c *Client) supports(capability string) bool {
+	return c.capabilities == nil || c.capabilities[capability]
+}
+
+// requireCapability returns an error naming the attribute when the API does
+// not offer capability.
+func (c *Client) requireCapability(capability, attribute string) error {
+	if c.supports(capability) {
+		return nil
+	}
+	return fmt.Errorf("%s: not supported by API version %s", attribute, c.apiVersion)
+}
+
+type apiVersion struct {
+	major, minor int
+}
+
+// parseAPIVersion parses "major.minor", ignoring any patch or pre-release
+// suffix, e.g. "2.3.1" or "v2.3".
+func parseAPIVersion(s string) (apiVersion, error) {
+	parts := strings.SplitN(strings.TrimPrefix(strings.TrimSpace(s), "v"), ".", 3)
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return apiVersion{}, fmt.Errorf("%q is not a version such as 1.4", s)
+	}
+	v := apiVersion{major: major}
+	if len(parts) > 1 {
+		minor, err := strconv.Atoi(strings.SplitN(parts[1], "-", 2)[0])
+		if err != nil {
+			return apiVersion{}, fmt.Errorf("%q is not a version such as 1.4", s)
+		}
+		v.minor = minor
+	}
+	return v, nil
+}
+
+func (v apiVersion) less(o apiVersion) bool {
+	return v.major < o.major || (v.major == o.major && v.minor < o.minor)
+}
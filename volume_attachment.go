@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// updateServerVolumes moves a server from the volumes it had to the ones now
+// configured by detaching and attaching them one at a time, waiting for each
+// to settle before the next. Detaches go first, so that a volume moved
+// between servers in one apply is free by the time it is attached. A change
+// of order alone changes nothing on the server.
+//
+// It runs after the rest of the update has been applied, so if a step
+// fails, recording the volumes attached at that point in state lets the
+// next apply carry on from there.
+func updateServerVolumes(ctx context.Context, c *Client, d *schema.ResourceData, project, server string) diag.Diagnostics {
+	o, n := d.GetChange("volumes")
+	oldVolumes := expandStringList(o.([]interface{}))
+	newVolumes := expandStringList(n.([]interface{}))
+
+	wanted := map[string]bool{}
+	for _, volume := range newVolumes {
+		wanted[volume] = true
+	}
+	had := map[string]bool{}
+	for _, volume := range oldVolumes {
+		had[volume] = true
+	}
+
+	attached := append([]string{}, oldVolumes...)
+	fail := func(diags diag.Diagnostics) diag.Diagnostics {
+		if err := d.Set("volumes", attached); err != nil {
+			return append(diags, diag.Errorf("Error setting volumes: %s", err)...)
+		}
+		return diags
+	}
+
+	timeout := d.Timeout(schema.TimeoutUpdate)
+	for _, volume := range oldVolumes {
+		if wanted[volume] {
+			continue
+		}
+		tflog.Info(ctx, "Detaching volume", map[string]interface{}{"server": server, "volume": volume})
+		if err := c.api.DetachVolume(ctx, project, server, volume); err != nil {
+			return fail(apiErrorDiag(fmt.Sprintf("Failed to detach volume '%s' from server '%s'", volume, server), err))
+		}
+		if diags := waitForVolumeStatus(ctx, c, project, volume, "detaching", "available", timeout); diags.HasError() {
+			return fail(diags)
+		}
+
+		remaining := attached[:0]
+		for _, v := range attached {
+			if v != volume {
+				remaining = append(remaining, v)
+			}
+		}
+		attached = remaining
+	}
+
+	for _, volume := range newVolumes {
+		if had[volume] {
+			continue
+		}
+		tflog.Info(ctx, "Attaching volume", map[string]interface{}{"server": server, "volume": volume})
+		if err := c.api.AttachVolume(ctx, project, server, volume); err != nil {
+			return fail(apiErrorDiag(fmt.Sprintf("Failed to attach volume '%s' to server '%s'", volume, server), err))
+		}
+		if diags := waitForVolumeStatus(ctx, c, project, volume, "attaching", "in-use", timeout); diags.HasError() {
+			return fail(diags)
+		}
+		attached = append(attached, volume)
+	}
+
+	return nil
+}
+
+// waitForVolumeStatus polls a volume, along the provider's poll_schedule,
+// until its status is target. The API may report the status the volume had
+// before the request, or transitional, until the change takes effect; any
+// other status fails the wait.
+func waitForVolumeStatus(ctx context.Context, c *Client, project, volume, transitional, target string, timeout time.Duration) diag.Diagnostics {
+	previous := map[string]string{"in-use": "available", "available": "in-use"}[target]
+
+	attempt := 0
+	wait := &retry.StateChangeConf{
+		Pending: []string{previous, transitional},
+		Target:  []string{target},
+		Refresh: func() (interface{}, string, error) {
+			if attempt > 0 {
+				select {
+				case <-time.After(pollDelay(c.pollSchedule, attempt-1)):
+				case <-ctx.Done():
+					return nil, "", ctx.Err()
+				}
+			}
+			attempt++
+
+			resp, err := c.api.GetVolume(ctx, project, volume)
+			if faxter.IsNotFound(err) {
+				return nil, "", fmt.Errorf("volume '%s' no longer exists", volume)
+			}
+			if err != nil {
+				return nil, "", fmt.Errorf("fetching volume status: %w", err)
+			}
+			tflog.Debug(ctx, "Polled volume status", map[string]interface{}{
+				"volume": volume,
+				"status": resp.Status,
+			})
+			return resp, resp.Status, nil
+		},
+		Timeout: timeout,
+		// The refresh paces the polls along the poll_schedule itself.
+		PollInterval: time.Millisecond,
+	}
+	if _, err := wait.WaitForStateContext(ctx); err != nil {
+		if ctx.Err() != nil {
+			return diag.FromErr(ctx.Err())
+		}
+		var timeoutErr *retry.TimeoutError
+		if errors.As(err, &timeoutErr) {
+			return diag.Errorf("Timed out waiting for volume '%s' to become %s", volume, target)
+		}
+		return diag.Errorf("Error waiting for volume '%s' to become %s: %s", volume, target, err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/ahmadmicro/terraform-provider-faxter/pkg/faxter"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// windowsBootTimeout bounds the waits for a Windows server's first boot to
+// finish after the API reports it online: sysprep and password generation
+// routinely take several minutes.
+const windowsBootTimeout = 15 * time.Minute
+
+// winrmDialTimeout bounds each attempt to connect to the WinRM listener.
+const winrmDialTimeout = 5 * time.Second
+
+// waitForAdminPassword polls for the administrator password generated on
+// first boot.
+func waitForAdminPassword(ctx context.Context, c *Client, project, name string) (string, error) {
+	deadline := time.Now().Add(windowsBootTimeout)
+
+	for attempt := 0; ; attempt++ {
+		password, err := c.api.GetServerPassword(ctx, project, name)
+		if err != nil && !faxter.IsNotFound(err) {
+			return "", fmt.Errorf("failed to retrieve administrator password: %w", err)
+		}
+		if err == nil && password.Password != "" {
+			return password.Password, nil
+		}
+
+		delay := pollDelay(c.pollSchedule, attempt)
+		if time.Now().Add(delay).After(deadline) {
+			return "", fmt.Errorf("timed out after %s waiting for server '%s' to generate its administrator password", windowsBootTimeout, name)
+		}
+		tflog.Debug(ctx, "Administrator password not yet available", map[string]interface{}{
+			"server": name,
+			"wait":   delay.String(),
+		})
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+}
+
+// waitForWinRM waits until the WinRM listener accepts connections on any of
+// the server's addresses, so that provisioners and configuration tools can
+// connect as soon as the create finishes.
+func waitForWinRM(ctx context.Context, c *Client, name string, addresses []string, port int) error {
+	if len(addresses) == 0 {
+		return fmt.Errorf("server '%s' has no IP address to reach WinRM on", name)
+	}
+	deadline := time.Now().Add(windowsBootTimeout)
+	dialer := &net.Dialer{Timeout: winrmDialTimeout}
+
+	for attempt := 0; ; attempt++ {
+		for _, address := range addresses {
+			conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(address, strconv.Itoa(port)))
+			if err == nil {
+				conn.Close()
+				tflog.Info(ctx, "WinRM is reachable", map[string]interface{}{
+					"server":  name,
+					"address": address,
+					"port":    port,
+				})
+				return nil
+			}
+		}
+
+		delay := pollDelay(c.pollSchedule, attempt)
+		if time.Now().Add(delay).After(deadline) {
+			return fmt.Errorf("timed out after %s waiting for WinRM on server '%s' (port %d) to accept connections", windowsBootTimeout, name, port)
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}